@@ -0,0 +1,417 @@
+// Command client plays on a game server from a terminal. It creates or joins a
+// session over the REST API, then plays over the same WebSocket protocol as the web
+// app, typing the commands of cmd/game's human seats. With -script it holds each
+// command until it is the player's turn, so piped commands drive a whole game.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golem_century/internal/console"
+	"golem_century/internal/game"
+
+	"github.com/gorilla/websocket"
+)
+
+// recentActions is how much history the board shows
+const recentActions = 6
+
+// options are the command-line flags
+type options struct {
+	server    string
+	session   string
+	players   int
+	bots      string
+	challenge string
+	seed      int64
+	name      string
+	passcode  string
+	seat      int
+	token     string
+	script    bool
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.server, "server", "http://localhost:8080", "Server address")
+	flag.StringVar(&opts.session, "session", "", "Session to join (default: create one)")
+	flag.IntVar(&opts.players, "players", 2, "Players in a new session (2-5)")
+	flag.StringVar(&opts.bots, "bots", "", "Comma-separated server bots for the seats after yours in a new session: difficulty[:personality], e.g. expert,beginner:rusher")
+	flag.StringVar(&opts.challenge, "challenge", "", "Create a solo session for this built-in challenge")
+	flag.Int64Var(&opts.seed, "seed", 0, "Seed for a new session (default: random)")
+	flag.StringVar(&opts.name, "name", "", "Your name at the table")
+	flag.StringVar(&opts.passcode, "passcode", "", "Passcode of a private session; creating with one makes the session private")
+	flag.IntVar(&opts.seat, "seat", 0, "Preferred seat (default: the first free one)")
+	flag.StringVar(&opts.token, "token", "", "Seat token to reconnect to your seat")
+	flag.BoolVar(&opts.script, "script", false, "Hold each command until it is your turn and exit when input ends, for piped commands")
+	flag.Parse()
+
+	if err := play(opts, os.Stdin, os.Stdout); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// play creates or joins a session and plays it with commands read from in
+func play(opts options, in io.Reader, out io.Writer) error {
+	base, err := url.Parse(opts.server)
+	if err != nil {
+		return fmt.Errorf("Invalid server address: %v", err)
+	}
+
+	sessionID, hostToken := opts.session, ""
+	if sessionID == "" {
+		created, err := createSession(base, opts.players, opts.seed, opts.bots, opts.challenge, opts.passcode)
+		if err != nil {
+			return fmt.Errorf("Failed to create session: %v", err)
+		}
+		sessionID, hostToken = created.SessionID, created.HostToken
+		fmt.Fprintf(out, "Created session %s for %d players; others join with -session %s\n", created.SessionID, created.NumPlayers, created.SessionID)
+	} else if err := joinSession(base, sessionID, opts.passcode, opts.token); err != nil {
+		return fmt.Errorf("Failed to join session: %v", err)
+	}
+
+	query := url.Values{"session": {sessionID}}
+	for key, value := range map[string]string{"name": opts.name, "passcode": opts.passcode, "hostToken": hostToken, "token": opts.token} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if opts.seat > 0 {
+		query.Set("player", fmt.Sprint(opts.seat))
+	}
+	endpoint := *base
+	endpoint.Scheme = strings.Replace(base.Scheme, "http", "ws", 1)
+	endpoint.Path = strings.TrimSuffix(base.Path, "/") + "/ws"
+	endpoint.RawQuery = query.Encode()
+
+	conn, resp, err := websocket.DefaultDialer.Dial(endpoint.String(), nil)
+	if err != nil {
+		if resp != nil {
+			body, _ := io.ReadAll(resp.Body)
+			err = fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(body))
+		}
+		return fmt.Errorf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	c := &client{conn: conn, out: out, script: opts.script}
+	if err := c.run(in); err != nil {
+		return fmt.Errorf("Disconnected: %v", err)
+	}
+	return nil
+}
+
+// created is the reply to /api/create
+type created struct {
+	SessionID  string `json:"sessionID"`
+	NumPlayers int    `json:"numPlayers"`
+	HostToken  string `json:"hostToken"`
+}
+
+// createSession creates a session, with server bots in the seats after the creator's
+func createSession(base *url.URL, numPlayers int, seed int64, bots, challengeID, passcode string) (created, error) {
+	type botSeat struct {
+		Seat int `json:"seat"`
+		game.BotSettings
+	}
+	req := map[string]interface{}{"numPlayers": numPlayers, "seed": seed}
+	if bots != "" {
+		seats := make([]botSeat, 0)
+		for i, spec := range strings.Split(bots, ",") {
+			settings, err := game.ParseBotSettings(strings.TrimSpace(spec))
+			if err != nil {
+				return created{}, err
+			}
+			seats = append(seats, botSeat{Seat: i + 2, BotSettings: settings})
+		}
+		req["bots"] = seats
+	}
+	if challengeID != "" {
+		req["challenge"] = challengeID
+	}
+	if passcode != "" {
+		req["private"], req["passcode"] = true, passcode
+	}
+
+	var reply created
+	body, _ := json.Marshal(req)
+	resp, err := http.Post(base.JoinPath("api", "create").String(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return reply, err
+	}
+	defer resp.Body.Close()
+	if err := readReply(resp, &reply); err != nil {
+		return reply, err
+	}
+	return reply, nil
+}
+
+// joinSession checks that the session exists and the passcode or seat token lets us in
+func joinSession(base *url.URL, sessionID, passcode, token string) error {
+	endpoint := base.JoinPath("api", "join")
+	endpoint.RawQuery = url.Values{"session": {sessionID}, "passcode": {passcode}, "token": {token}}.Encode()
+	resp, err := http.Get(endpoint.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return readReply(resp, nil)
+}
+
+// readReply decodes a JSON reply into v, or returns the server's error message
+func readReply(resp *http.Response, v interface{}) error {
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s", apiErr.Error)
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// client is one seat at a server table
+type client struct {
+	conn    *websocket.Conn
+	out     io.Writer
+	script  bool
+	me      int // Seat the server assigned, 0 until it has
+	state   *state
+	history []string
+	pending []game.Action // Actions of the current command still to send
+	waiting bool          // An action was sent and the server hasn't answered yet
+}
+
+// run plays until the game ends, the player quits or the connection drops
+func (c *client) run(in io.Reader) error {
+	// done stops the reader goroutines from blocking on a send once run has returned
+	done := make(chan struct{})
+	defer close(done)
+	messages, readErr := make(chan []byte), make(chan error, 1)
+	go func() {
+		for {
+			_, data, err := c.conn.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case messages <- data:
+			case <-done:
+				return
+			}
+		}
+	}()
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	inputDone := false
+	for {
+		// In script mode the next command waits until it can be played
+		input := lines
+		if inputDone || c.script && !c.ready() {
+			input = nil
+		}
+		if inputDone && !c.waiting && c.script {
+			return nil
+		}
+		select {
+		case data := <-messages:
+			if over, err := c.handleMessage(data); over || err != nil {
+				return err
+			}
+		case err := <-readErr:
+			return err
+		case line, ok := <-input:
+			if !ok {
+				if !c.script {
+					return nil
+				}
+				inputDone = true
+				continue
+			}
+			if quit := c.handleLine(line); quit {
+				return nil
+			}
+		}
+	}
+}
+
+// ready reports whether a command can be played now
+func (c *client) ready() bool {
+	return c.state != nil && (c.state.GameOver || c.state.CurrentPlayer == c.me && !c.waiting)
+}
+
+// handleMessage handles a server message and reports whether the game is over
+func (c *client) handleMessage(data []byte) (bool, error) {
+	var msg struct {
+		Type      string `json:"type"`
+		PlayerID  int    `json:"playerID"`
+		SeatToken string `json:"seatToken"`
+		Error     string `json:"error"`
+		Name      string `json:"name"`
+		Text      string `json:"text"`
+		Emote     string `json:"emote"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return false, err
+	}
+	switch msg.Type {
+	case "playerAssigned":
+		c.me = msg.PlayerID
+		fmt.Fprintf(c.out, "You are seat %d. To reconnect, use -session with -token %s\n", c.me, msg.SeatToken)
+	case "state":
+		var s state
+		if err := json.Unmarshal(data, &s); err != nil {
+			return false, err
+		}
+		if move := describeMove(c.state, &s); move != "" {
+			c.history = append(c.history, move)
+		}
+		c.state = &s
+		if c.waiting {
+			c.waiting = false
+			if len(c.pending) > 0 {
+				c.send(c.pending[0])
+				c.pending = c.pending[1:]
+				return false, nil
+			}
+		}
+		render(c.out, c.state, c.me, c.history)
+		return s.GameOver, nil
+	case "error":
+		fmt.Fprintf(c.out, "  Cannot do that: %s\n", msg.Error)
+		c.waiting, c.pending = false, nil
+	case "chat":
+		fmt.Fprintf(c.out, "[chat] %s: %s\n", msg.Name, msg.Text)
+	case "emote":
+		fmt.Fprintf(c.out, "[chat] %s %s\n", msg.Name, msg.Emote)
+	case "kicked":
+		return true, fmt.Errorf("kicked by the host")
+	}
+	return false, nil
+}
+
+// handleLine runs a typed command and reports whether the player quit
+func (c *client) handleLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+	if text, ok := strings.CutPrefix(line, "say "); ok {
+		c.write(map[string]interface{}{"type": "chat", "text": text})
+		return false
+	}
+	cmd, err := console.Parse(line)
+	if err != nil {
+		fmt.Fprintf(c.out, "  %v\n", err)
+		return false
+	}
+	switch cmd.Verb {
+	case console.Help:
+		fmt.Fprintln(c.out, console.Usage)
+		fmt.Fprintln(c.out, "  say TEXT                    chat with the table")
+		return false
+	case console.Board:
+		if c.state != nil {
+			render(c.out, c.state, c.me, c.history)
+		}
+		return false
+	case console.History:
+		for _, move := range c.history {
+			fmt.Fprintf(c.out, "  %s\n", move)
+		}
+		return false
+	case console.Quit:
+		return true
+	}
+
+	switch {
+	case c.state == nil:
+		fmt.Fprintln(c.out, "  The game hasn't started yet")
+	case c.state.GameOver:
+		fmt.Fprintln(c.out, "  The game is over")
+	case c.state.CurrentPlayer != c.me:
+		fmt.Fprintf(c.out, "  Not your turn: waiting for %s\n", c.state.player(c.state.CurrentPlayer).Name)
+	case c.waiting:
+		fmt.Fprintln(c.out, "  Waiting for the server to answer")
+	default:
+		actions, err := cmd.Actions(cardNames(c.state.player(c.me).Hand))
+		if err != nil {
+			fmt.Fprintf(c.out, "  Cannot do that: %v\n", err)
+			return false
+		}
+		c.send(actions[0])
+		c.pending = actions[1:]
+	}
+	return false
+}
+
+// send sends an action; the server answers with a state or an error
+func (c *client) send(action game.Action) {
+	c.waiting = true
+	if err := c.write(actionMessage(action)); err != nil {
+		fmt.Fprintf(c.out, "  Failed to send: %v\n", err)
+		c.waiting, c.pending = false, nil
+	}
+}
+
+func (c *client) write(msg map[string]interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// actionMessage encodes an action the way the web app sends it
+func actionMessage(action game.Action) map[string]interface{} {
+	msg := map[string]interface{}{
+		"type":       "action",
+		"actionType": action.Type.String(),
+		"cardIndex":  action.CardIndex,
+	}
+	switch action.Type {
+	case game.PlayCard:
+		msg["multiplier"] = action.Multiplier
+		if action.InputResources != nil && action.OutputResources != nil {
+			msg["inputResources"] = action.InputResources
+			msg["outputResources"] = action.OutputResources
+		}
+	case game.DepositCrystals:
+		deposits := make(map[string]string, len(action.Deposits))
+		for position, list := range action.Deposits {
+			deposits[fmt.Sprint(position)] = strings.ToLower(game.CrystalTypeNames[list[0]])
+		}
+		msg["deposits"] = deposits
+		msg["targetPosition"] = action.TargetPosition
+	case game.DiscardCrystals:
+		msg["discard"] = action.Discard
+	}
+	return msg
+}
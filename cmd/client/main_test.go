@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golem_century/internal/server"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	gs := server.NewGameServer()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", gs.HandleWebSocket)
+	mux.HandleFunc("/api/create", gs.HandleCreateSession)
+	mux.HandleFunc("/api/join", gs.HandleJoinSession)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// A script creates a solo challenge, joins it and plays until the golem is claimed
+func TestScriptPlaysChallenge(t *testing.T) {
+	srv := newTestServer(t)
+	script := strings.Join([]string{
+		"board",
+		"play 0",         // mint_0002: YYY -> YYYYY
+		"claim 9",        // No such golem: refused, the script goes on
+		"say good luck!", // Chat with the table
+		"trade 1 x2",     // trade_0002_0020 twice: YGGGG
+		"upgrade Y->G",   // upgrade_2: GGGGG
+		"claim 0",        // golem_0050 ends the challenge
+	}, "\n")
+	var out bytes.Buffer
+	err := play(options{server: srv.URL, challenge: "first-golem", name: "Ann", script: true},
+		strings.NewReader(script), &out)
+	if err != nil {
+		t.Fatalf("play: %v\n%s", err, out.String())
+	}
+
+	for _, want := range []string{
+		"Created session",
+		"You are seat 1",
+		"Ann played mint_0002",
+		"Ann played trade_0002_0020",
+		"Ann played upgrade_2",
+		"Cannot do that",
+		"[chat] Ann: good luck!",
+		"Ann claimed golem_0050",
+		"Game over.",
+		"Challenge completed (stars: 3",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestJoinUnknownSession(t *testing.T) {
+	srv := newTestServer(t)
+	err := play(options{server: srv.URL, session: "nosuchsession"}, strings.NewReader(""), &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "Failed to join session") {
+		t.Errorf("got %v, want a join error", err)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// crystals is a resources object on the wire
+type crystals struct {
+	Yellow int `json:"yellow"`
+	Green  int `json:"green"`
+	Blue   int `json:"blue"`
+	Pink   int `json:"pink"`
+}
+
+// String writes the crystals as letters, e.g. "YYG"
+func (c *crystals) String() string {
+	if c == nil || c.Yellow+c.Green+c.Blue+c.Pink == 0 {
+		return "-"
+	}
+	return strings.Repeat("Y", c.Yellow) + strings.Repeat("G", c.Green) +
+		strings.Repeat("B", c.Blue) + strings.Repeat("P", c.Pink)
+}
+
+type card struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Input       *crystals         `json:"input"`
+	Output      *crystals         `json:"output"`
+	TurnUpgrade int               `json:"turnUpgrade"`
+	Requirement *crystals         `json:"requirement"`
+	Points      int               `json:"points"`
+	Amount      int               `json:"amount"`
+	Deposits    map[string]string `json:"deposits"` // Position -> comma-separated crystal names
+	Coin        *card             `json:"coin"`     // Coin pile above a golem
+}
+
+// effect describes what an action card does, e.g. "YY -> GG"
+func (c card) effect() string {
+	switch {
+	case strings.HasPrefix(c.Name, "mint_"):
+		return "+" + c.Output.String()
+	case strings.HasPrefix(c.Name, "upgrade_"):
+		return fmt.Sprintf("upgrade %d step(s)", c.TurnUpgrade)
+	case strings.HasPrefix(c.Name, "trade_"):
+		return c.Input.String() + " -> " + c.Output.String()
+	}
+	return ""
+}
+
+// deposits lists the crystals left on the card
+func (c card) deposits() string {
+	letters := ""
+	for _, list := range c.Deposits {
+		for _, name := range strings.Split(list, ",") {
+			if name != "" {
+				letters += strings.ToUpper(name[:1])
+			}
+		}
+	}
+	if letters == "" {
+		return ""
+	}
+	return "  deposits: " + letters
+}
+
+type player struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Resources   crystals `json:"resources"`
+	Points      int      `json:"points"`
+	Hand        []card   `json:"hand"`
+	PlayedCards []card   `json:"playedCards"`
+	PointCards  []card   `json:"pointCards"`
+	Coins       []card   `json:"coins"`
+	IsAI        bool     `json:"isAI"`
+}
+
+// state is the "state" message the server broadcasts after every change
+type state struct {
+	CurrentTurn   int  `json:"currentTurn"`
+	CurrentPlayer int  `json:"currentPlayer"`
+	Round         int  `json:"round"`
+	GameOver      bool `json:"gameOver"`
+	LastRound     bool `json:"lastRound"`
+	Winner        *struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		Points int    `json:"points"`
+	} `json:"winner"`
+	Players []player `json:"players"`
+	Market  struct {
+		ActionCards []card `json:"actionCards"`
+		PointCards  []card `json:"pointCards"`
+		ActionDeck  int    `json:"actionDeck"`
+		PointDeck   int    `json:"pointDeck"`
+	} `json:"market"`
+	Challenge *struct {
+		Name   string `json:"name"`
+		Goal   string `json:"goal"`
+		Result struct {
+			Status    string `json:"status"`
+			TurnsLeft int    `json:"turnsLeft"`
+			Stars     int    `json:"stars"`
+			Score     int    `json:"score"`
+		} `json:"result"`
+	} `json:"challenge"`
+}
+
+func (s *state) player(id int) *player {
+	for i := range s.Players {
+		if s.Players[i].ID == id {
+			return &s.Players[i]
+		}
+	}
+	return nil
+}
+
+func cardNames(cards []card) []string {
+	names := make([]string, len(cards))
+	for i, c := range cards {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// render draws the board as seat me sees it
+func render(w io.Writer, s *state, me int, history []string) {
+	current := s.player(s.CurrentPlayer)
+	if current == nil {
+		return
+	}
+	fmt.Fprintf(w, "\n=== Round %d, turn %d: %s to play ===\n", s.Round, s.CurrentTurn+1, current.Name)
+	if s.LastRound {
+		fmt.Fprintln(w, "Last round!")
+	}
+	if c := s.Challenge; c != nil {
+		fmt.Fprintf(w, "Challenge: %s - %s (%d turns left)\n", c.Name, c.Goal, c.Result.TurnsLeft)
+	}
+
+	fmt.Fprintln(w, "\nGolems:")
+	for i, golem := range s.Market.PointCards {
+		coin := ""
+		if golem.Coin != nil {
+			coin = fmt.Sprintf("  +%d-point coin (%d left)", golem.Coin.Points, golem.Coin.Amount)
+		}
+		fmt.Fprintf(w, "  [%d] %-12s %2d pts  needs %-8s%s\n", i, golem.Name, golem.Points, golem.Requirement.String(), coin)
+	}
+
+	fmt.Fprintln(w, "\nMarket:")
+	for i, c := range s.Market.ActionCards {
+		cost := "free"
+		if i > 0 {
+			cost = fmt.Sprintf("%d Y or deposits", i)
+		}
+		fmt.Fprintf(w, "  [%d] %-16s %-18s %s%s\n", i, c.Name, c.effect(), cost, c.deposits())
+	}
+	fmt.Fprintf(w, "  (%d action cards and %d golems left in the decks)\n", s.Market.ActionDeck, s.Market.PointDeck)
+
+	fmt.Fprintln(w, "\nPlayers:")
+	for _, p := range s.Players {
+		marker := " "
+		if p.ID == s.CurrentPlayer {
+			marker = ">"
+		}
+		name := p.Name
+		if p.ID == me {
+			name += " (you)"
+		}
+		fmt.Fprintf(w, "%s %-20s crystals %-10s %3d pts  golems %d  hand %d  played %d\n",
+			marker, name, p.Resources.String(), p.Points, len(p.PointCards), len(p.Hand), len(p.PlayedCards))
+	}
+
+	if mine := s.player(me); mine != nil {
+		fmt.Fprintln(w, "\nYour hand:")
+		for i, c := range mine.Hand {
+			fmt.Fprintf(w, "  [%d] %-16s %s\n", i, c.Name, c.effect())
+		}
+		if len(mine.PlayedCards) > 0 {
+			fmt.Fprintf(w, "Played (back on rest): %s\n", strings.Join(cardNames(mine.PlayedCards), ", "))
+		}
+	}
+
+	if len(history) > 0 {
+		fmt.Fprintln(w, "\nRecent actions:")
+		for _, line := range history[max(0, len(history)-recentActions):] {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+
+	switch {
+	case s.GameOver:
+		printResults(w, s)
+	case s.CurrentPlayer == me:
+		fmt.Fprintln(w, "\nYour turn. Type help for the commands.")
+	default:
+		fmt.Fprintf(w, "\nWaiting for %s...\n", current.Name)
+	}
+}
+
+func printResults(w io.Writer, s *state) {
+	fmt.Fprintln(w, "\nGame over.")
+	players := append([]player(nil), s.Players...)
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].Points > players[j].Points
+	})
+	for i, p := range players {
+		fmt.Fprintf(w, "  %d. %-20s %3d pts  golems %d  crystals %s\n", i+1, p.Name, p.Points, len(p.PointCards), p.Resources.String())
+	}
+	if s.Winner != nil {
+		fmt.Fprintf(w, "Winner: %s with %d points\n", s.Winner.Name, s.Winner.Points)
+	}
+	if c := s.Challenge; c != nil {
+		fmt.Fprintf(w, "Challenge %s (stars: %d, score: %d)\n", c.Result.Status, c.Result.Stars, c.Result.Score)
+	}
+}
+
+// describeMove works out from two states what the seat that just moved did. The
+// server only broadcasts states, so moves are read off the difference.
+func describeMove(before, after *state) string {
+	if before == nil || before.CurrentTurn == after.CurrentTurn && !after.GameOver {
+		return ""
+	}
+	was, now := before.player(before.CurrentPlayer), after.player(before.CurrentPlayer)
+	if was == nil || now == nil {
+		return ""
+	}
+	var move string
+	switch {
+	case len(now.PointCards) > len(was.PointCards):
+		move = "claimed " + now.PointCards[len(now.PointCards)-1].Name
+	case len(now.Hand)+len(now.PlayedCards) > len(was.Hand)+len(was.PlayedCards):
+		move = "acquired " + now.Hand[len(now.Hand)-1].Name
+	case len(now.PlayedCards) > len(was.PlayedCards):
+		move = "played " + now.PlayedCards[len(now.PlayedCards)-1].Name
+	case len(now.PlayedCards) < len(was.PlayedCards):
+		move = "rested"
+	default:
+		move = "discarded"
+	}
+	return fmt.Sprintf("%s %s (crystals %s -> %s)", was.Name, move, was.Resources.String(), now.Resources.String())
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golem_century/internal/console"
+	"golem_century/internal/game"
+)
+
+// recentActions is how much history the board shows
+const recentActions = 6
+
+// human is a seat played by someone typing commands at the terminal. Commands are
+// checked on a copy of the game before they are played, so a mistake only costs a
+// retry. Plain stdin/stdout is enough, so it works over SSH.
+type human struct {
+	engine *game.Engine
+	in     *bufio.Scanner
+	out    io.Writer
+}
+
+// newHuman reads commands from in and writes the board to out. Seats share the
+// reader, so several humans can take turns at one terminal.
+func newHuman(engine *game.Engine, in *bufio.Scanner, out io.Writer) *human {
+	return &human{engine: engine, in: in, out: out}
+}
+
+// ChooseAction prompts until the player enters an action the game accepts. Deposits
+// for an acquisition are played here; the acquisition is returned to the engine.
+func (h *human) ChooseAction(player *game.Player, market *game.Market, gs *game.GameState) game.Action {
+	h.showBoard(player, gs)
+	for {
+		fmt.Fprintf(h.out, "%s> ", player.Name)
+		if !h.in.Scan() {
+			h.quit(gs)
+		}
+		line := strings.TrimSpace(h.in.Text())
+		if line == "" {
+			continue
+		}
+		cmd, err := console.Parse(line)
+		if err != nil {
+			fmt.Fprintf(h.out, "  %v\n", err)
+			continue
+		}
+		switch cmd.Verb {
+		case console.Help:
+			fmt.Fprintln(h.out, console.Usage)
+			continue
+		case console.Board:
+			h.showBoard(player, gs)
+			continue
+		case console.History:
+			h.showHistory(len(h.engine.History))
+			continue
+		case console.Quit:
+			h.quit(gs)
+		}
+
+		actions, err := cmd.Actions(cardNames(player.Hand))
+		if err == nil {
+			err = tryActions(gs, actions)
+		}
+		if err != nil {
+			fmt.Fprintf(h.out, "  Cannot do that: %v\n", err)
+			continue
+		}
+		for _, action := range actions[:len(actions)-1] {
+			h.engine.Record(player, gs.DescribeAction(action))
+			gs.ExecuteAction(action)
+		}
+		return actions[len(actions)-1]
+	}
+}
+
+// tryActions plays the actions on a copy of the game and returns the first error
+func tryActions(gs *game.GameState, actions []game.Action) error {
+	trial := gs.Clone()
+	for _, action := range actions {
+		if err := trial.ExecuteAction(action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quit ends the program with the standings so far
+func (h *human) quit(gs *game.GameState) {
+	fmt.Fprintln(h.out, "\nGame abandoned.")
+	gs.PrintFinalResults()
+	os.Exit(0)
+}
+
+func cardNames(cards []*game.Card) []string {
+	names := make([]string, len(cards))
+	for i, card := range cards {
+		names[i] = card.Name
+	}
+	return names
+}
+
+// showBoard renders the game as player sees it: every seat's public state, the
+// market, and player's own cards
+func (h *human) showBoard(player *game.Player, gs *game.GameState) {
+	w := h.out
+	fmt.Fprintf(w, "\n=== Round %d, turn %d: %s ===\n", gs.Round, gs.CurrentTurn+1, player.Name)
+	if gs.LastRound {
+		fmt.Fprintln(w, "Last round!")
+	}
+
+	fmt.Fprintln(w, "\nGolems:")
+	for i, card := range gs.Market.PointCards {
+		coin := ""
+		if pile := gs.Market.CoinOn(i); pile != nil {
+			coin = fmt.Sprintf("  +%d-point coin (%d left)", pile.Points, pile.Amount)
+		}
+		fmt.Fprintf(w, "  [%d] %-12s %2d pts  needs %-8s%s\n", i, card.Name, card.Points, crystals(card.Requirement), coin)
+	}
+
+	fmt.Fprintln(w, "\nMarket:")
+	for i, card := range gs.Market.ActionCards {
+		cost := "free"
+		if i > 0 {
+			cost = fmt.Sprintf("%d Y or deposits", i)
+		}
+		fmt.Fprintf(w, "  [%d] %-16s %-18s %s%s\n", i, card.Name, effect(card), cost, deposits(card))
+	}
+	fmt.Fprintf(w, "  (%d action cards and %d golems left in the decks)\n", len(gs.Market.ActionDeck), len(gs.Market.PointDeck))
+
+	fmt.Fprintln(w, "\nPlayers:")
+	for _, p := range gs.Players {
+		marker := " "
+		if p == player {
+			marker = ">"
+		}
+		fmt.Fprintf(w, "%s %-20s crystals %-10s %3d pts  golems %d  hand %d  played %d\n",
+			marker, p.Name, crystals(p.Resources), p.GetPoints(), len(p.PointCards), len(p.Hand), len(p.PlayedCards))
+	}
+
+	fmt.Fprintln(w, "\nYour hand:")
+	for i, card := range player.Hand {
+		fmt.Fprintf(w, "  [%d] %-16s %s\n", i, card.Name, effect(card))
+	}
+	if len(player.PlayedCards) > 0 {
+		fmt.Fprintf(w, "Played (back on rest): %s\n", strings.Join(cardNames(player.PlayedCards), ", "))
+	}
+	if player.PendingDiscard > 0 {
+		fmt.Fprintf(w, "\nYou hold more than %d crystals: discard %d, e.g. discard Y,Y\n", game.MaxCrystals, player.PendingDiscard)
+	}
+
+	h.showHistory(recentActions)
+	fmt.Fprintln(w, "\nType help for the commands.")
+}
+
+// showHistory prints the last n actions
+func (h *human) showHistory(n int) {
+	history := h.engine.History
+	if len(history) == 0 {
+		return
+	}
+	fmt.Fprintln(h.out, "\nRecent actions:")
+	for _, line := range history[max(0, len(history)-n):] {
+		fmt.Fprintf(h.out, "  %s\n", line)
+	}
+}
+
+// crystals writes resources as letters, e.g. "YYG"
+func crystals(r *game.Resources) string {
+	if r == nil || r.Total() == 0 {
+		return "-"
+	}
+	return strings.Repeat("Y", r.Yellow) + strings.Repeat("G", r.Green) +
+		strings.Repeat("B", r.Blue) + strings.Repeat("P", r.Pink)
+}
+
+// effect describes what an action card does, e.g. "YY -> GG"
+func effect(card *game.Card) string {
+	switch card.ActionType {
+	case game.Produce:
+		return "+" + crystals(card.Output)
+	case game.Upgrade:
+		return fmt.Sprintf("upgrade %d step(s)", card.TurnUpgrade)
+	case game.Trade:
+		return crystals(card.Input) + " -> " + crystals(card.Output)
+	}
+	return ""
+}
+
+// deposits lists the crystals left on a market card
+func deposits(card *game.Card) string {
+	r := game.NewResources()
+	for _, list := range card.Deposits {
+		for _, crystal := range list {
+			r.Add(crystal, 1)
+		}
+	}
+	if r.Total() == 0 {
+		return ""
+	}
+	return "  deposits: " + crystals(r)
+}
@@ -1,31 +1,152 @@
 package main
 
 import (
+	"bufio"
 	"flag"
 	"fmt"
+	"math/rand"
+	"os"
+	"strings"
 	"time"
 
+	"golem_century/internal/challenge"
 	"golem_century/internal/game"
 )
 
 func main() {
 	// Command line flags
-	numPlayers := flag.Int("players", 3, "Number of players (2-4)")
+	numPlayers := flag.Int("players", 3, "Number of players (2-5)")
 	seed := flag.Int64("seed", time.Now().UnixNano(), "Random seed for reproducibility")
+	botName := flag.String("bot", "greedy", "Bot playing every seat (greedy or lookahead)")
+	depth := flag.Int("depth", 2, "Lookahead bot search depth (1-3)")
+	weightsFile := flag.String("weights", "", "JSON file with lookahead bot weights (default: built-in weights)")
+	seats := flag.String("seats", "", "Comma-separated player per seat: human[:name], greedy or difficulty[:personality], e.g. human,expert,beginner:rusher")
+	challengeName := flag.String("challenge", "", "Play a solo challenge: a built-in ID or a .json challenge file")
+	listChallenges := flag.Bool("challenges", false, "List the built-in challenges and exit")
+	setupFile := flag.String("setup", "", "Scenario file (JSON game setup) fixing seats, hands, decks, coins and rules")
 	flag.Parse()
 
+	if *listChallenges {
+		for _, c := range challenge.Builtin() {
+			fmt.Printf("%-14s %s - %s\n", c.ID, c.Name, c.GoalText())
+		}
+		return
+	}
+
+	var solo *challenge.Challenge
+	if *challengeName != "" {
+		var err error
+		if solo, err = challenge.Load(*challengeName); err != nil {
+			fmt.Printf("Failed to load challenge: %v\n", err)
+			os.Exit(1)
+		}
+		*numPlayers = 1
+	}
+	var scenario *game.GameState
+	if *setupFile != "" && solo == nil {
+		setup, err := game.LoadSetup(*setupFile)
+		if err == nil {
+			scenario, err = setup.NewGame()
+		}
+		if err != nil {
+			fmt.Printf("Failed to load scenario: %v\n", err)
+			os.Exit(1)
+		}
+		*numPlayers = len(scenario.Players)
+	}
+
 	// Validate number of players
-	if *numPlayers < 2 || *numPlayers > 4 {
-		fmt.Printf("Invalid number of players: %d. Must be between 2 and 4.\n", *numPlayers)
+	if solo == nil && scenario == nil && (*numPlayers < game.MinPlayers || *numPlayers > game.MaxPlayers) {
+		fmt.Printf("Invalid number of players: %d. Must be between %d and %d.\n", *numPlayers, game.MinPlayers, game.MaxPlayers)
 		*numPlayers = 3
 		fmt.Printf("Using default: %d players\n", *numPlayers)
 	}
 
 	fmt.Printf("Century: Golem Edition - CLI Simulation\n")
-	fmt.Printf("Players: %d, Seed: %d\n\n", *numPlayers, *seed)
+	if solo != nil {
+		fmt.Printf("Challenge: %s - %s\n\n", solo.Name, solo.GoalText())
+	} else {
+		fmt.Printf("Players: %d, Seed: %d\n\n", *numPlayers, *seed)
+	}
 
 	// Create and run game engine
 	engine := game.NewEngine(*numPlayers, *seed)
+	if scenario != nil {
+		engine.GameState = scenario
+		engine.AI = game.NewAIPlayer(scenario.RNG)
+	}
+	if solo != nil {
+		gameState, err := solo.NewGame()
+		if err != nil {
+			fmt.Printf("Failed to set up challenge: %v\n", err)
+			os.Exit(1)
+		}
+		engine.GameState = gameState
+		engine.AI = game.NewAIPlayer(engine.GameState.RNG)
+		engine.CheckEnd = func(gs *game.GameState) bool {
+			_, over := solo.Finish(gs)
+			return over
+		}
+	}
+	weights := game.DefaultWeights()
+	if *weightsFile != "" {
+		var err error
+		if weights, err = game.LoadWeights(*weightsFile); err != nil {
+			fmt.Printf("Failed to load weights: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	switch *botName {
+	case "greedy":
+	case "lookahead":
+		engine.AI = game.NewLookaheadBot(weights, *depth)
+	default:
+		fmt.Printf("Unknown bot: %s. Must be greedy or lookahead.\n", *botName)
+		os.Exit(1)
+	}
+
+	if *seats != "" {
+		specs := strings.Split(*seats, ",")
+		if len(specs) > *numPlayers {
+			fmt.Printf("Too many seats: %d bots for %d players\n", len(specs), *numPlayers)
+			os.Exit(1)
+		}
+		input := bufio.NewScanner(os.Stdin)
+		for i, spec := range specs {
+			player := engine.GameState.Players[i]
+			spec = strings.TrimSpace(spec)
+			if name, ok := strings.CutPrefix(spec, "human"); ok && (name == "" || name[0] == ':') {
+				engine.Bots[player.ID] = newHuman(engine, input, os.Stdout)
+				engine.Quiet = true
+				if name != "" {
+					player.Name = name[1:]
+				}
+				continue
+			}
+			if spec == "greedy" {
+				engine.Bots[player.ID] = game.NewAIPlayer(engine.GameState.RNG)
+				player.Name = "Greedy Bot"
+				continue
+			}
+			settings, err := game.ParseBotSettings(spec)
+			if err != nil {
+				fmt.Printf("Seat %d: %v\n", player.ID, err)
+				os.Exit(1)
+			}
+			bot, err := game.NewBot(settings, weights, rand.New(rand.NewSource(*seed+int64(player.ID))))
+			if err != nil {
+				fmt.Printf("Seat %d: %v\n", player.ID, err)
+				os.Exit(1)
+			}
+			engine.Bots[player.ID] = bot
+			player.Name = settings.Name()
+		}
+	}
 	engine.Run()
+
+	if solo != nil {
+		result := solo.Check(engine.GameState)
+		fmt.Printf("\nChallenge %s in %d turns (stars: %d, score: %d)\n", result.Status, result.Turns, result.Stars, result.Score)
+	}
 }
 
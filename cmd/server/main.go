@@ -5,23 +5,133 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"golem_century/internal/account"
+	"golem_century/internal/game"
+	"golem_century/internal/history"
+	"golem_century/internal/outbox"
+	"golem_century/internal/rating"
 	"golem_century/internal/server"
 )
 
 func main() {
 	port := flag.Int("port", 8080, "Port to run the server on")
+	chatBlocklist := flag.String("chat-blocklist", "", "File with one word per line to mask in chat")
+	maxSessions := flag.Int("max-sessions", server.DefaultMaxSessions, "Maximum concurrent sessions (0 = unlimited)")
+	createRate := flag.Int("create-rate", 5, "Sessions one IP may create per minute (0 = unlimited)")
+	allowedOrigins := flag.String("allowed-origins", "", "Comma-separated extra WebSocket origins (e.g. https://golem.example.com), * allows any")
+	tlsCert := flag.String("tls-cert", "", "TLS certificate file (enables HTTPS with -tls-key)")
+	tlsKey := flag.String("tls-key", "", "TLS private key file")
+	certDir := flag.String("cert-dir", "", "Directory of <hostname>.crt/<hostname>.key pairs picked by SNI (enables HTTPS)")
+	dataDir := flag.String("data-dir", "data", "Directory for persistent data (accounts, ratings, match history)")
+	outboxDir := flag.String("outbox", "", "Directory where outgoing emails are written (default <data-dir>/outbox)")
+	adminToken := flag.String("admin-token", os.Getenv("GOLEM_ADMIN_TOKEN"), "Bearer token for the admin API (default $GOLEM_ADMIN_TOKEN, empty disables it)")
+	allowWebhooks := flag.Bool("allow-webhooks", false, "Let correspondence players get turn notifications by webhook (the server will POST to any URL they give)")
+	botWeights := flag.String("bot-weights", "", "JSON file with evaluation weights for server bots (default: built-in weights)")
+	httpRedirect := flag.String("http-redirect", "", "When serving HTTPS, also listen on this address (e.g. :80) and redirect to HTTPS")
+	publicURL := flag.String("public-url", os.Getenv("GOLEM_PUBLIC_URL"), "Base URL of the site for sign-in links in emails, e.g. https://golem.example.com (default $GOLEM_PUBLIC_URL, empty disables them)")
 	flag.Parse()
 
+	tlsEnabled := *certDir != "" || (*tlsCert != "" && *tlsKey != "")
+	if (*tlsCert == "") != (*tlsKey == "") {
+		log.Fatal("-tls-cert and -tls-key must be used together")
+	}
+
+	if *publicURL != "" {
+		u, err := url.Parse(*publicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			log.Fatalf("-public-url must be an http(s) URL such as https://golem.example.com, got %q", *publicURL)
+		}
+	}
+
 	gameServer := server.NewGameServer()
+	gameServer.MaxSessions = *maxSessions
+	gameServer.SetCreateRateLimit(*createRate)
+	gameServer.SecureCookies = tlsEnabled
+	gameServer.AdminToken = *adminToken
+	gameServer.AllowWebhooks = *allowWebhooks
+	gameServer.PublicURL = *publicURL
+	if *allowedOrigins != "" {
+		for _, origin := range strings.Split(*allowedOrigins, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				gameServer.AllowedOrigins = append(gameServer.AllowedOrigins, origin)
+			}
+		}
+	}
+	if *botWeights != "" {
+		weights, err := game.LoadWeights(*botWeights)
+		if err != nil {
+			log.Fatalf("Failed to load bot weights: %v", err)
+		}
+		gameServer.BotWeights = &weights
+	}
+	if *chatBlocklist != "" {
+		data, err := os.ReadFile(*chatBlocklist)
+		if err != nil {
+			log.Fatalf("Failed to read chat blocklist: %v", err)
+		}
+		gameServer.ChatFilter = server.NewWordFilter(strings.Split(string(data), "\n"))
+	}
+
+	if *outboxDir == "" {
+		*outboxDir = filepath.Join(*dataDir, "outbox")
+	}
+	mail, err := outbox.New(*outboxDir)
+	if err != nil {
+		log.Fatalf("Failed to create outbox: %v", err)
+	}
+	gameServer.Outbox = mail
+	gameServer.Accounts, err = account.Open(*dataDir, mail)
+	if err != nil {
+		log.Fatalf("Failed to open account store: %v", err)
+	}
+	cookieKey, err := account.LoadOrCreateKey(filepath.Join(*dataDir, "cookie.key"))
+	if err != nil {
+		log.Fatalf("Failed to load cookie key: %v", err)
+	}
+	gameServer.Signer = account.NewSigner(cookieKey)
+	gameServer.Ratings, err = rating.Open(*dataDir)
+	if err != nil {
+		log.Fatalf("Failed to open rating store: %v", err)
+	}
+	gameServer.History, err = history.Open(filepath.Join(*dataDir, "games"))
+	if err != nil {
+		log.Fatalf("Failed to open match history: %v", err)
+	}
 
 	// Setup routes
 	http.HandleFunc("/ws", gameServer.HandleWebSocket)
+	http.HandleFunc("/ws/queue", gameServer.HandleQueue)
+	http.HandleFunc("/ws/bot", gameServer.HandleBotWebSocket)
 	http.HandleFunc("/api/create", gameServer.HandleCreateSession)
 	http.HandleFunc("/api/join", gameServer.HandleJoinSession)
 	http.HandleFunc("/api/list", gameServer.HandleListSessions)
+	http.HandleFunc("GET /api/challenges", gameServer.HandleListChallenges)
+	http.HandleFunc("GET /api/queue", gameServer.HandleQueueStatus)
+	http.HandleFunc("/api/register", gameServer.HandleRegister)
+	http.HandleFunc("/api/login", gameServer.HandleLogin)
+	http.HandleFunc("/api/logout", gameServer.HandleLogout)
+	http.HandleFunc("/api/magic-link", gameServer.HandleMagicLink)
+	http.HandleFunc("/api/magic-login", gameServer.HandleMagicLogin)
+	http.HandleFunc("/api/me", gameServer.HandleMe)
+	http.HandleFunc("/api/leaderboard", gameServer.HandleLeaderboard)
+	http.HandleFunc("/api/history", gameServer.HandleHistory)
+	http.HandleFunc("GET /api/games/{id}", gameServer.HandleGameDetail)
+	http.HandleFunc("POST /api/games/{id}/seats", gameServer.HandleTakeSeat)
+	http.HandleFunc("GET /api/games/{id}/state", gameServer.HandleGetState)
+	http.HandleFunc("GET /api/games/{id}/paths", gameServer.HandleGetPaths)
+	http.HandleFunc("POST /api/games/{id}/actions", gameServer.HandleSubmitAction)
+	http.HandleFunc("POST /api/games/{id}/notify", gameServer.HandleSetNotify)
+	http.HandleFunc("POST /api/tournaments", gameServer.HandleCreateTournament)
+	http.HandleFunc("GET /api/tournaments/{id}", gameServer.HandleGetTournament)
+	http.HandleFunc("POST /api/tournaments/{id}/rounds", gameServer.HandleStartRound)
+	http.HandleFunc("POST /api/tournaments/{id}/drop", gameServer.HandleDropPlayer)
+	http.HandleFunc("POST /api/tournaments/{id}/tables/{table}/result", gameServer.HandleTableResult)
 	
 	// Always serve images from static directory (both React and vanilla JS need this)
 	staticDir := filepath.Join(".", "web", "static")
@@ -50,10 +160,41 @@ func main() {
 	}
 
 	addr := fmt.Sprintf(":%d", *port)
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           server.SecurityHeaders(http.DefaultServeMux, tlsEnabled),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if !tlsEnabled {
+		fmt.Printf("Century: Golem Edition - Web Server\n")
+		fmt.Printf("Server starting on http://localhost%s\n", addr)
+		fmt.Printf("Open http://localhost%s in your browser to play\n", addr)
+		log.Fatal(httpServer.ListenAndServe())
+	}
+
+	if *certDir != "" {
+		tlsConfig, err := server.NewCertDirConfig(*certDir)
+		if err != nil {
+			log.Fatalf("Failed to use certificate directory: %v", err)
+		}
+		httpServer.TLSConfig = tlsConfig
+	}
+
+	if *httpRedirect != "" {
+		go func() {
+			log.Printf("Redirecting http://%s to HTTPS", *httpRedirect)
+			redirect := &http.Server{
+				Addr:              *httpRedirect,
+				Handler:           server.RedirectToHTTPS(*port),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			log.Fatal(redirect.ListenAndServe())
+		}()
+	}
+
 	fmt.Printf("Century: Golem Edition - Web Server\n")
-	fmt.Printf("Server starting on http://localhost%s\n", addr)
-	fmt.Printf("Open http://localhost%s in your browser to play\n", addr)
-	
-	log.Fatal(http.ListenAndServe(addr, nil))
+	fmt.Printf("Server starting on https://localhost%s\n", addr)
+	// With a cert directory the files come from TLSConfig, so the arguments are empty
+	log.Fatal(httpServer.ListenAndServeTLS(*tlsCert, *tlsKey))
 }
-
@@ -0,0 +1,320 @@
+package account
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golem_century/internal/outbox"
+)
+
+// Errors returned by the store
+var (
+	ErrUsernameTaken      = errors.New("username already taken")
+	ErrEmailTaken         = errors.New("email already registered")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidToken       = errors.New("invalid or expired login link")
+	ErrNotFound           = errors.New("account not found")
+)
+
+const (
+	minPasswordLength = 8
+	magicLinkTTL      = 15 * time.Minute
+	usersFile         = "users.json"
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{3,20}$`)
+
+// User is a player account
+type User struct {
+	ID           string       `json:"id"`
+	Username     string       `json:"username"`
+	Email        string       `json:"email,omitempty"`
+	PasswordHash string       `json:"passwordHash,omitempty"` // Empty for magic-link-only accounts
+	CreatedAt    time.Time    `json:"createdAt"`
+	Results      []GameResult `json:"results"`
+}
+
+// GameResult records how an account did in one finished game
+type GameResult struct {
+	SessionID  string    `json:"sessionID"`
+	Seat       int       `json:"seat"`
+	Placement  int       `json:"placement"` // 1 = winner
+	NumPlayers int       `json:"numPlayers"`
+	Points     int       `json:"points"`
+	PlayedAt   time.Time `json:"playedAt"`
+}
+
+// magicLink is a pending one-time login token. Links for unknown emails carry no
+// user: the account is only created when the link is used.
+type magicLink struct {
+	userID  string
+	email   string
+	expires time.Time
+}
+
+// Store keeps accounts in memory and persists them to a JSON file
+type Store struct {
+	path       string
+	outbox     *outbox.Outbox
+	users      map[string]*User // ID -> user
+	magicLinks map[string]magicLink
+	mu         sync.RWMutex
+}
+
+// Open loads (or creates) the account store in dir.
+// Login links are written to the outbox instead of being emailed.
+func Open(dir string, mail *outbox.Outbox) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	s := &Store{
+		path:       filepath.Join(dir, usersFile),
+		outbox:     mail,
+		users:      make(map[string]*User),
+		magicLinks: make(map[string]magicLink),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var users []*User
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", s.path, err)
+	}
+	for _, u := range users {
+		s.users[u.ID] = u
+	}
+	return s, nil
+}
+
+// Register creates a password account
+func (s *Store) Register(username, email, password string) (*User, error) {
+	if !usernamePattern.MatchString(username) {
+		return nil, fmt.Errorf("username must be 3-20 letters, digits, '_' or '-'")
+	}
+	if len(password) < minPasswordLength {
+		return nil, fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	email = normalizeEmail(email)
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findByUsername(username) != nil {
+		return nil, ErrUsernameTaken
+	}
+	if email != "" && s.findByEmail(email) != nil {
+		return nil, ErrEmailTaken
+	}
+	user := &User{
+		ID:           newID(),
+		Username:     username,
+		Email:        email,
+		PasswordHash: hash,
+		CreatedAt:    time.Now(),
+		Results:      make([]GameResult, 0),
+	}
+	s.users[user.ID] = user
+	if err := s.save(); err != nil {
+		delete(s.users, user.ID)
+		return nil, err
+	}
+	return user.copy(), nil
+}
+
+// Authenticate checks a username and password
+func (s *Store) Authenticate(username, password string) (*User, error) {
+	s.mu.RLock()
+	user := s.findByUsername(username)
+	s.mu.RUnlock()
+	if user == nil || user.PasswordHash == "" || !checkPassword(user.PasswordHash, password) {
+		return nil, ErrInvalidCredentials
+	}
+	return user.copy(), nil
+}
+
+// SendMagicLink creates a one-time login token for email and writes the link to the outbox.
+// Unknown emails get a new account named after the address once the link is used.
+func (s *Store) SendMagicLink(email, baseURL string) error {
+	email = normalizeEmail(email)
+	if email == "" || !strings.Contains(email, "@") {
+		return fmt.Errorf("invalid email address")
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	for token, link := range s.magicLinks {
+		if now.After(link.expires) {
+			delete(s.magicLinks, token)
+		}
+	}
+	link := magicLink{email: email, expires: now.Add(magicLinkTTL)}
+	username := email
+	if user := s.findByEmail(email); user != nil {
+		link.userID, username = user.ID, user.Username
+	}
+	token := newID() + newID()
+	s.magicLinks[token] = link
+	s.mu.Unlock()
+
+	url := fmt.Sprintf("%s/api/magic-login?token=%s", strings.TrimSuffix(baseURL, "/"), token)
+	body := fmt.Sprintf("Hi %s,\n\nUse this link to sign in to Century: Golem Edition (valid for %d minutes):\n\n%s\n",
+		username, int(magicLinkTTL.Minutes()), url)
+	_, err := s.outbox.Send(email, "Your Golem sign-in link", body)
+	return err
+}
+
+// ConsumeMagicLink exchanges a login token for its account, creating the account
+// for a new email. Tokens work once.
+func (s *Store) ConsumeMagicLink(token string) (*User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.magicLinks[token]
+	delete(s.magicLinks, token)
+	if !ok || time.Now().After(link.expires) {
+		return nil, ErrInvalidToken
+	}
+	if link.userID != "" {
+		user, ok := s.users[link.userID]
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+		return user.copy(), nil
+	}
+	// The address may have been registered since the link was sent
+	if user := s.findByEmail(link.email); user != nil {
+		return user.copy(), nil
+	}
+	user := &User{
+		ID:        newID(),
+		Username:  s.uniqueUsername(link.email),
+		Email:     link.email,
+		CreatedAt: time.Now(),
+		Results:   make([]GameResult, 0),
+	}
+	s.users[user.ID] = user
+	if err := s.save(); err != nil {
+		delete(s.users, user.ID)
+		return nil, err
+	}
+	return user.copy(), nil
+}
+
+// Get returns an account by ID
+func (s *Store) Get(id string) (*User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return user.copy(), nil
+}
+
+// RecordResult appends a finished game to an account's history
+func (s *Store) RecordResult(id string, result GameResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	user.Results = append(user.Results, result)
+	return s.save()
+}
+
+// save writes all users to disk atomically (caller must hold s.mu)
+func (s *Store) save() error {
+	users := make([]*User, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	data, err := json.MarshalIndent(users, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// findByUsername looks up a user case-insensitively (caller must hold s.mu)
+func (s *Store) findByUsername(username string) *User {
+	for _, u := range s.users {
+		if strings.EqualFold(u.Username, username) {
+			return u
+		}
+	}
+	return nil
+}
+
+// findByEmail looks up a user by normalized email (caller must hold s.mu)
+func (s *Store) findByEmail(email string) *User {
+	for _, u := range s.users {
+		if u.Email != "" && u.Email == email {
+			return u
+		}
+	}
+	return nil
+}
+
+// uniqueUsername derives a free username from an email (caller must hold s.mu)
+func (s *Store) uniqueUsername(email string) string {
+	base := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return -1
+	}, strings.SplitN(email, "@", 2)[0])
+	if len(base) > 16 {
+		base = base[:16]
+	}
+	for len(base) < 3 {
+		base += "_"
+	}
+	name := base
+	for i := 2; s.findByUsername(name) != nil; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	return name
+}
+
+// copy returns a copy safe to hand out without holding the lock
+func (u *User) copy() *User {
+	c := *u
+	c.Results = append([]GameResult{}, u.Results...)
+	return &c
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// newID returns a random 128-bit hex identifier
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
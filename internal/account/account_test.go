@@ -0,0 +1,64 @@
+package account
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"golem_century/internal/outbox"
+)
+
+func TestMagicLinkCreatesAccountOnUse(t *testing.T) {
+	dir := t.TempDir()
+	mail, err := outbox.New(filepath.Join(dir, "outbox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := Open(dir, mail)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SendMagicLink("ann@example.com", "http://localhost"); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.users) != 0 {
+		t.Fatalf("sending a link created %d accounts", len(store.users))
+	}
+
+	sent, err := os.ReadDir(mail.Dir)
+	if err != nil || len(sent) != 1 {
+		t.Fatalf("outbox holds %d mails (%v), want 1", len(sent), err)
+	}
+	body, err := os.ReadFile(filepath.Join(mail.Dir, sent[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := regexp.MustCompile(`token=(\w+)`).FindStringSubmatch(string(body))
+	if token == nil {
+		t.Fatalf("no token in %q", body)
+	}
+
+	user, err := store.ConsumeMagicLink(token[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "ann@example.com" || !strings.HasPrefix(user.Username, "ann") {
+		t.Errorf("got account %q <%s>", user.Username, user.Email)
+	}
+	if _, err := store.ConsumeMagicLink(token[1]); err != ErrInvalidToken {
+		t.Errorf("second use: got %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestLoadOrCreateKeyRejectsShortKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookie.key")
+	if err := os.WriteFile(path, []byte("00ff"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOrCreateKey(path); err == nil {
+		t.Error("a 2-byte key was accepted")
+	}
+}
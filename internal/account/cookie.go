@@ -0,0 +1,91 @@
+package account
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCookie is returned for tampered, malformed or expired cookies
+var ErrInvalidCookie = errors.New("invalid session cookie")
+
+// Signer signs and verifies session cookie values with HMAC-SHA256
+type Signer struct {
+	key []byte
+}
+
+// NewSigner creates a signer from a secret key
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// minKeySize is the shortest signing key accepted, in bytes
+const minKeySize = 32
+
+// LoadOrCreateKey reads a signing key from path, generating one on first use
+// so sessions survive server restarts. A key shorter than 32 bytes is an error.
+func LoadOrCreateKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", path, err)
+		}
+		if len(key) < minKeySize {
+			return nil, fmt.Errorf("signing key %s is %d bytes, need at least %d", path, len(key), minKeySize)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	key := make([]byte, minKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Sign returns a cookie value binding userID until expires
+func (s *Signer) Sign(userID string, expires time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(userID + "|" + strconv.FormatInt(expires.Unix(), 10)))
+	return payload + "." + s.mac(payload)
+}
+
+// Verify checks a cookie value and returns the user ID it was signed for
+func (s *Signer) Verify(value string) (string, error) {
+	payload, sig, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.mac(payload))) {
+		return "", ErrInvalidCookie
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	userID, expiresStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return "", ErrInvalidCookie
+	}
+	expires, err := strconv.ParseInt(expiresStr, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return "", ErrInvalidCookie
+	}
+	return userID, nil
+}
+
+func (s *Signer) mac(payload string) string {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
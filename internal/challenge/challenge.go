@@ -0,0 +1,240 @@
+// Package challenge defines single-player puzzles: a scripted setup, a goal to reach
+// within a turn limit and a rubric that scores how well it was reached.
+//
+// Challenges are JSON files. The built-in ones live in challenges/ and are embedded.
+package challenge
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"golem_century/internal/game"
+)
+
+//go:embed challenges/*.json
+var builtinFiles embed.FS
+
+// turnBonus is the score for every turn left unused when the goal is reached
+const turnBonus = 5
+
+// Goal is what the player has to do within MaxTurns turns. Set Golem, Points or both.
+type Goal struct {
+	Golem    string `json:"golem,omitempty"`  // Claim this golem, e.g. "golem_2300"
+	Points   int    `json:"points,omitempty"` // Reach this many final points (golems, coins and crystals)
+	MaxTurns int    `json:"maxTurns"`
+}
+
+// Tier awards Stars for reaching the goal in at most Turns turns
+type Tier struct {
+	Stars int `json:"stars"`
+	Turns int `json:"turns"`
+}
+
+// Challenge is a scripted single-player game
+type Challenge struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Seed        int64          `json:"seed"`        // Shuffles any deck the challenge leaves unlisted
+	Resources   game.Resources `json:"resources"`   // Starting crystals
+	Hand        []string       `json:"hand"`        // Starting hand; empty means the usual two cards
+	ActionCards []string       `json:"actionCards"` // Market row then deck, in order; empty means the shuffled default deck
+	PointCards  []string       `json:"pointCards"`  // Golem row then deck, in order; empty means the shuffled default deck
+	Goal        Goal           `json:"goal"`
+	Rubric      []Tier         `json:"rubric"` // Reaching the goal earns the most stars of the tiers it qualifies for
+}
+
+// Status is how a challenge attempt stands
+type Status string
+
+const (
+	Playing   Status = "playing"
+	Completed Status = "completed"
+	Failed    Status = "failed"
+)
+
+// Result is the outcome of a challenge attempt so far
+type Result struct {
+	Status    Status `json:"status"`
+	Turns     int    `json:"turns"`     // Turns taken
+	TurnsLeft int    `json:"turnsLeft"` // Turns before the limit
+	Stars     int    `json:"stars"`
+	Score     int    `json:"score"` // Final points plus a bonus per unused turn; 0 unless completed
+}
+
+// Parse reads a challenge from JSON and validates it
+func Parse(data []byte) (*Challenge, error) {
+	var c Challenge
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// LoadFile reads a challenge file
+func LoadFile(filename string) (*Challenge, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("challenge %s: %w", filename, err)
+	}
+	return c, nil
+}
+
+// Builtin returns the embedded challenges, ordered by ID
+func Builtin() []*Challenge {
+	entries, _ := builtinFiles.ReadDir("challenges")
+	challenges := make([]*Challenge, 0, len(entries))
+	for _, entry := range entries {
+		data, err := builtinFiles.ReadFile(path.Join("challenges", entry.Name()))
+		if err != nil {
+			continue
+		}
+		if c, err := Parse(data); err == nil {
+			challenges = append(challenges, c)
+		}
+	}
+	sort.Slice(challenges, func(i, j int) bool {
+		return challenges[i].ID < challenges[j].ID
+	})
+	return challenges
+}
+
+// Get returns the built-in challenge with the given ID
+func Get(id string) (*Challenge, bool) {
+	for _, c := range Builtin() {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Load finds a challenge by built-in ID, or else reads it from a file
+func Load(idOrFile string) (*Challenge, error) {
+	if c, ok := Get(idOrFile); ok {
+		return c, nil
+	}
+	if !strings.HasSuffix(idOrFile, ".json") {
+		return nil, fmt.Errorf("unknown challenge %q", idOrFile)
+	}
+	return LoadFile(idOrFile)
+}
+
+// Validate checks that the challenge has a goal and a turn limit and that every card name is known
+func (c *Challenge) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("challenge has no id")
+	}
+	if c.Goal.Golem == "" && c.Goal.Points <= 0 {
+		return fmt.Errorf("goal needs a golem or points")
+	}
+	if c.Goal.MaxTurns <= 0 {
+		return fmt.Errorf("goal needs maxTurns")
+	}
+	if err := c.Setup().Validate(); err != nil {
+		return err
+	}
+	if c.Goal.Golem != "" && !strings.HasPrefix(c.Goal.Golem, "golem_") {
+		return fmt.Errorf("goal golem %q is not a golem card", c.Goal.Golem)
+	}
+	for _, tier := range c.Rubric {
+		if tier.Turns <= 0 || tier.Turns > c.Goal.MaxTurns {
+			return fmt.Errorf("rubric tier for %d stars needs turns between 1 and %d", tier.Stars, c.Goal.MaxTurns)
+		}
+	}
+	return nil
+}
+
+// Setup returns the challenge's setup: one seat and the listed decks in order
+func (c *Challenge) Setup() game.GameSetup {
+	resources := c.Resources
+	return game.GameSetup{
+		Seed:        c.Seed,
+		Seats:       []game.SeatSetup{{Resources: &resources, Hand: c.Hand}},
+		ActionCards: c.ActionCards,
+		PointCards:  c.PointCards,
+	}
+}
+
+// NewGame sets up the challenge as a one-player game
+func (c *Challenge) NewGame() (*game.GameState, error) {
+	return c.Setup().NewGame()
+}
+
+// Check scores the game so far. Call it after each turn-ending action, before the
+// turn advances: the player has then taken CurrentTurn+1 turns.
+func (c *Challenge) Check(gs *game.GameState) Result {
+	player := gs.Players[0]
+	turns := gs.CurrentTurn + 1
+	result := Result{Status: Playing, Turns: turns, TurnsLeft: max(0, c.Goal.MaxTurns-turns)}
+
+	if c.reached(player) {
+		result.Status = Completed
+		result.Score = player.GetFinalPoints() + turnBonus*result.TurnsLeft
+		for _, tier := range c.Rubric {
+			if turns <= tier.Turns && tier.Stars > result.Stars {
+				result.Stars = tier.Stars
+			}
+		}
+		return result
+	}
+	if turns >= c.Goal.MaxTurns || gs.GameOver {
+		result.Status = Failed
+	}
+	return result
+}
+
+func (c *Challenge) reached(player *game.Player) bool {
+	if c.Goal.Points > 0 && player.GetFinalPoints() < c.Goal.Points {
+		return false
+	}
+	if c.Goal.Golem == "" {
+		return true
+	}
+	for _, golem := range player.PointCards {
+		if golem.Name == c.Goal.Golem {
+			return true
+		}
+	}
+	return false
+}
+
+// Finish ends the game once the attempt is decided; the player wins only by completing
+// it. Call it after GameState.CheckGameOver. It reports whether the game is over.
+func (c *Challenge) Finish(gs *game.GameState) (Result, bool) {
+	result := c.Check(gs)
+	if result.Status == Playing {
+		return result, false
+	}
+	gs.GameOver = true
+	gs.Winner = nil
+	if result.Status == Completed {
+		gs.Winner = gs.Players[0]
+	}
+	return result, true
+}
+
+// GoalText describes the goal, e.g. "Claim golem_2300 within 12 turns"
+func (c *Challenge) GoalText() string {
+	parts := make([]string, 0, 2)
+	if c.Goal.Golem != "" {
+		parts = append(parts, "claim "+c.Goal.Golem)
+	}
+	if c.Goal.Points > 0 {
+		parts = append(parts, fmt.Sprintf("reach %d points", c.Goal.Points))
+	}
+	text := strings.Join(parts, " and ")
+	return strings.ToUpper(text[:1]) + text[1:] + fmt.Sprintf(" within %d turns", c.Goal.MaxTurns)
+}
@@ -0,0 +1,316 @@
+// Package console parses the commands players type at a terminal, such as
+// "trade 2 x3" or "acquire 3 deposit Y,Y,G", into game actions.
+//
+// Indexes are the ones the boards print: hand and market cards count from 0.
+// Crystals are written as letters (Y, G, B, P) or names, separated by commas:
+// "Y,Y", "YY" and "yellow,yellow" are the same two crystals.
+package console
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golem_century/internal/game"
+)
+
+// Verb is what a command does
+type Verb string
+
+const (
+	Play    Verb = "play"
+	Trade   Verb = "trade"
+	Upgrade Verb = "upgrade"
+	Acquire Verb = "acquire"
+	Claim   Verb = "claim"
+	Rest    Verb = "rest"
+	Discard Verb = "discard"
+
+	// Commands that don't act on the game
+	Help    Verb = "help"
+	Board   Verb = "board"
+	History Verb = "history"
+	Quit    Verb = "quit"
+)
+
+// aliases maps shorthands to verbs
+var aliases = map[string]Verb{
+	"p": Play, "t": Trade, "u": Upgrade, "a": Acquire, "buy": Acquire, "c": Claim, "r": Rest,
+	"h": Help, "?": Help, "b": Board, "log": History, "q": Quit, "exit": Quit,
+}
+
+// Usage lists the commands
+const Usage = `Commands (cards count from 0, crystals are Y G B P):
+  play N                      play hand card N (a mint, or a trade once)
+  trade N [xM]                play trade card N, M times
+  upgrade [N] Y,Y->G,G        play upgrade card N (default: the first in hand)
+  acquire N [deposit Y,G,..]  take market card N, depositing one crystal on each card before it
+  claim N                     claim golem N
+  rest                        take your played cards back
+  discard Y,Y                 drop crystals over the limit
+  board, history, help, quit`
+
+// Command is a parsed command line
+type Command struct {
+	Verb       Verb
+	Index      int // Card index; -1 when not given
+	Multiplier int // Trade multiplier
+	In, Out    *game.Resources
+	Crystals   []game.CrystalType // Deposits, one per card before the target, or the crystals to discard
+}
+
+// IsAction reports whether the command acts on the game
+func (c Command) IsAction() bool {
+	switch c.Verb {
+	case Help, Board, History, Quit:
+		return false
+	}
+	return true
+}
+
+// Parse reads one command line
+func Parse(line string) (Command, error) {
+	fields := strings.Fields(strings.ToLower(line))
+	if len(fields) == 0 {
+		return Command{}, fmt.Errorf("empty command (type help for the commands)")
+	}
+	verb := Verb(fields[0])
+	if alias, ok := aliases[fields[0]]; ok {
+		verb = alias
+	}
+	cmd := Command{Verb: verb, Index: -1, Multiplier: 1}
+	args := fields[1:]
+
+	switch verb {
+	case Help, Board, History, Quit, Rest:
+		if len(args) > 0 {
+			return cmd, fmt.Errorf("%s takes no arguments", verb)
+		}
+
+	case Play, Claim:
+		if len(args) != 1 {
+			return cmd, fmt.Errorf("usage: %s N", verb)
+		}
+		index, err := parseIndex(args[0])
+		if err != nil {
+			return cmd, err
+		}
+		cmd.Index = index
+
+	case Trade:
+		if len(args) < 1 || len(args) > 2 {
+			return cmd, fmt.Errorf("usage: trade N [xM]")
+		}
+		index, err := parseIndex(args[0])
+		if err != nil {
+			return cmd, err
+		}
+		cmd.Index = index
+		if len(args) == 2 {
+			m, err := strconv.Atoi(strings.TrimPrefix(args[1], "x"))
+			if err != nil || m < 1 || m > game.MaxCrystals {
+				return cmd, fmt.Errorf("bad multiplier %q: use x1, x2, ... up to x%d", args[1], game.MaxCrystals)
+			}
+			cmd.Multiplier = m
+		}
+
+	case Upgrade:
+		if len(args) == 2 {
+			index, err := parseIndex(args[0])
+			if err != nil {
+				return cmd, err
+			}
+			cmd.Index = index
+			args = args[1:]
+		}
+		if len(args) != 1 {
+			return cmd, fmt.Errorf("usage: upgrade [N] Y,Y->G,G")
+		}
+		in, out, ok := strings.Cut(args[0], "->")
+		if !ok {
+			return cmd, fmt.Errorf("upgrade needs IN->OUT, e.g. Y,Y->G,G")
+		}
+		var err error
+		if cmd.In, err = ParseResources(in); err != nil {
+			return cmd, err
+		}
+		if cmd.Out, err = ParseResources(out); err != nil {
+			return cmd, err
+		}
+
+	case Acquire:
+		if len(args) != 1 && len(args) != 3 {
+			return cmd, fmt.Errorf("usage: acquire N [deposit Y,G,...]")
+		}
+		index, err := parseIndex(args[0])
+		if err != nil {
+			return cmd, err
+		}
+		cmd.Index = index
+		if len(args) == 3 {
+			if args[1] != "deposit" {
+				return cmd, fmt.Errorf("usage: acquire N [deposit Y,G,...]")
+			}
+			if cmd.Crystals, err = ParseCrystals(args[2]); err != nil {
+				return cmd, err
+			}
+		}
+
+	case Discard:
+		if len(args) != 1 {
+			return cmd, fmt.Errorf("usage: discard Y,Y")
+		}
+		crystals, err := ParseCrystals(args[0])
+		if err != nil {
+			return cmd, err
+		}
+		cmd.Crystals = crystals
+
+	default:
+		return cmd, fmt.Errorf("unknown command %q (type help for the commands)", fields[0])
+	}
+	return cmd, nil
+}
+
+func parseIndex(s string) (int, error) {
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("bad card number %q", s)
+	}
+	return index, nil
+}
+
+// crystalNames maps letters and names to crystal types
+var crystalNames = map[string]game.CrystalType{
+	"y": game.Yellow, "yellow": game.Yellow,
+	"g": game.Green, "green": game.Green,
+	"b": game.Blue, "blue": game.Blue,
+	"p": game.Pink, "pink": game.Pink,
+}
+
+// ParseCrystals reads a list of crystals, e.g. "Y,Y,G", "YYG" or "yellow,green"
+func ParseCrystals(s string) ([]game.CrystalType, error) {
+	crystals := make([]game.CrystalType, 0)
+	for _, part := range strings.Split(strings.ToLower(s), ",") {
+		if crystal, ok := crystalNames[part]; ok {
+			crystals = append(crystals, crystal)
+			continue
+		}
+		if part == "" {
+			return nil, fmt.Errorf("bad crystals %q", s)
+		}
+		for _, letter := range part {
+			crystal, ok := crystalNames[string(letter)]
+			if !ok {
+				return nil, fmt.Errorf("bad crystal %q in %q: use Y, G, B or P", letter, s)
+			}
+			crystals = append(crystals, crystal)
+		}
+	}
+	return crystals, nil
+}
+
+// ParseResources reads a list of crystals as counts
+func ParseResources(s string) (*game.Resources, error) {
+	crystals, err := ParseCrystals(s)
+	if err != nil {
+		return nil, err
+	}
+	resources := game.NewResources()
+	for _, crystal := range crystals {
+		resources.Add(crystal, 1)
+	}
+	return resources, nil
+}
+
+// Actions turns the command into the game actions to send, given the names of the
+// cards in hand. Acquiring with deposits takes two actions: the deposit, which
+// doesn't end the turn, then the acquisition. The game still validates each action.
+func (c Command) Actions(hand []string) ([]game.Action, error) {
+	handCard := func(kind string) (string, error) {
+		if c.Index >= len(hand) {
+			return "", fmt.Errorf("no card %d in hand (you hold %d)", c.Index, len(hand))
+		}
+		name := hand[c.Index]
+		if kind != "" && !strings.HasPrefix(name, kind+"_") {
+			return "", fmt.Errorf("hand card %d is %s, not %s card", c.Index, name, article(kind))
+		}
+		return name, nil
+	}
+
+	switch c.Verb {
+	case Play:
+		name, err := handCard("")
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(name, "upgrade_") {
+			return nil, fmt.Errorf("%s is an upgrade: use upgrade %d IN->OUT, e.g. upgrade %d Y->G", name, c.Index, c.Index)
+		}
+		return []game.Action{{Type: game.PlayCard, CardIndex: c.Index, Multiplier: 1}}, nil
+
+	case Trade:
+		if _, err := handCard("trade"); err != nil {
+			return nil, err
+		}
+		return []game.Action{{Type: game.PlayCard, CardIndex: c.Index, Multiplier: c.Multiplier}}, nil
+
+	case Upgrade:
+		index := c.Index
+		if index < 0 {
+			for i, name := range hand {
+				if strings.HasPrefix(name, "upgrade_") {
+					index = i
+					break
+				}
+			}
+			if index < 0 {
+				return nil, fmt.Errorf("no upgrade card in hand")
+			}
+		} else if _, err := handCard("upgrade"); err != nil {
+			return nil, err
+		}
+		return []game.Action{{Type: game.PlayCard, CardIndex: index, Multiplier: 1, InputResources: c.In, OutputResources: c.Out}}, nil
+
+	case Acquire:
+		acquire := game.Action{Type: game.AcquireCard, CardIndex: c.Index}
+		if len(c.Crystals) == 0 {
+			return []game.Action{acquire}, nil
+		}
+		if len(c.Crystals) != c.Index {
+			return nil, fmt.Errorf("acquiring market card %d takes one deposit on each of the %d cards before it, not %d", c.Index, c.Index, len(c.Crystals))
+		}
+		deposits := make(map[int][]game.CrystalType, len(c.Crystals))
+		for i, crystal := range c.Crystals {
+			deposits[i+1] = []game.CrystalType{crystal}
+		}
+		deposit := game.Action{
+			Type:           game.DepositCrystals,
+			CardIndex:      len(hand) + c.Index,
+			Deposits:       deposits,
+			TargetPosition: c.Index + 1,
+		}
+		return []game.Action{deposit, acquire}, nil
+
+	case Claim:
+		return []game.Action{{Type: game.ClaimPointCard, CardIndex: c.Index}}, nil
+
+	case Rest:
+		return []game.Action{{Type: game.Rest}}, nil
+
+	case Discard:
+		discard := game.NewResources()
+		for _, crystal := range c.Crystals {
+			discard.Add(crystal, 1)
+		}
+		return []game.Action{{Type: game.DiscardCrystals, Discard: discard}}, nil
+	}
+	return nil, fmt.Errorf("%s is not a game action", c.Verb)
+}
+
+func article(noun string) string {
+	if strings.ContainsRune("aeiou", rune(noun[0])) {
+		return "an " + noun
+	}
+	return "a " + noun
+}
@@ -0,0 +1,135 @@
+package env
+
+import (
+	"fmt"
+
+	"golem_century/internal/game"
+)
+
+// Encoding limits. Cards beyond these slots are not encoded and cannot be chosen.
+const (
+	MaxHand        = 16               // Hand slots
+	MaxMarketCards = 6                // Market action card slots
+	MaxPointCards  = 5                // Market golem slots
+	MaxPlayers     = 5                // Player slots in the observation
+	MaxMultiplier  = game.MaxCrystals // Highest trade multiplier in the action space
+	maxUpgrade     = 3                // Strongest upgrade card in the game
+)
+
+// The action space is fixed:
+//
+//	[0, MaxHand*MaxMultiplier)                      play hand card i with multiplier m (produce cards use m=1)
+//	next MaxHand*U                                  play upgrade hand card i with upgrade option u
+//	next MaxMarketCards                             acquire market card i
+//	next MaxPointCards                              claim golem i
+//	last                                            rest
+//
+// where U is the number of distinct upgrades (see UpgradeCatalog).
+var (
+	upgradeCatalog = game.UpgradeOptions(&game.Resources{Yellow: maxUpgrade, Green: maxUpgrade, Blue: maxUpgrade, Pink: maxUpgrade}, maxUpgrade)
+	upgradeIndex   = indexUpgrades(upgradeCatalog)
+
+	playBase    = 0
+	upgradeBase = playBase + MaxHand*MaxMultiplier
+	acquireBase = upgradeBase + MaxHand*len(upgradeCatalog)
+	claimBase   = acquireBase + MaxMarketCards
+	restID      = claimBase + MaxPointCards
+
+	// NumActions is the size of the action space
+	NumActions = restID + 1
+)
+
+// UpgradeCatalog returns the upgrade options in action-space order
+func UpgradeCatalog() [][2]game.Resources {
+	catalog := make([][2]game.Resources, len(upgradeCatalog))
+	for i, pair := range upgradeCatalog {
+		catalog[i] = [2]game.Resources{*pair[0], *pair[1]}
+	}
+	return catalog
+}
+
+func upgradeKey(in, out *game.Resources) string {
+	return fmt.Sprintf("%d%d%d%d>%d%d%d%d", in.Yellow, in.Green, in.Blue, in.Pink, out.Yellow, out.Green, out.Blue, out.Pink)
+}
+
+func indexUpgrades(pairs [][2]*game.Resources) map[string]int {
+	index := make(map[string]int, len(pairs))
+	for i, pair := range pairs {
+		index[upgradeKey(pair[0], pair[1])] = i
+	}
+	return index
+}
+
+// ActionID returns the action-space ID of a game action (false if it has none)
+func ActionID(action game.Action) (int, bool) {
+	switch action.Type {
+	case game.PlayCard:
+		if action.CardIndex < 0 || action.CardIndex >= MaxHand {
+			return 0, false
+		}
+		if action.InputResources == nil || action.OutputResources == nil {
+			multiplier := max(action.Multiplier, 1)
+			if multiplier > MaxMultiplier {
+				return 0, false
+			}
+			return playBase + action.CardIndex*MaxMultiplier + multiplier - 1, true
+		}
+		u, ok := upgradeIndex[upgradeKey(action.InputResources, action.OutputResources)]
+		if !ok {
+			return 0, false
+		}
+		return upgradeBase + action.CardIndex*len(upgradeCatalog) + u, true
+	case game.AcquireCard:
+		if action.CardIndex < 0 || action.CardIndex >= MaxMarketCards {
+			return 0, false
+		}
+		return acquireBase + action.CardIndex, true
+	case game.ClaimPointCard:
+		if action.CardIndex < 0 || action.CardIndex >= MaxPointCards {
+			return 0, false
+		}
+		return claimBase + action.CardIndex, true
+	case game.Rest:
+		return restID, true
+	}
+	return 0, false
+}
+
+// Action returns the game action for an action-space ID
+func Action(id int) (game.Action, error) {
+	switch {
+	case id < 0 || id >= NumActions:
+		return game.Action{}, fmt.Errorf("action ID %d out of range", id)
+	case id < upgradeBase:
+		offset := id - playBase
+		return game.Action{Type: game.PlayCard, CardIndex: offset / MaxMultiplier, Multiplier: offset%MaxMultiplier + 1}, nil
+	case id < acquireBase:
+		offset := id - upgradeBase
+		pair := upgradeCatalog[offset%len(upgradeCatalog)]
+		return game.Action{
+			Type:            game.PlayCard,
+			CardIndex:       offset / len(upgradeCatalog),
+			Multiplier:      1,
+			InputResources:  pair[0].Copy(),
+			OutputResources: pair[1].Copy(),
+		}, nil
+	case id < claimBase:
+		return game.Action{Type: game.AcquireCard, CardIndex: id - acquireBase}, nil
+	case id < restID:
+		return game.Action{Type: game.ClaimPointCard, CardIndex: id - claimBase}, nil
+	}
+	return game.Action{Type: game.Rest}, nil
+}
+
+// legalMask marks the legal actions of the current player
+func legalMask(state *game.GameState) ([]bool, map[int]game.Action) {
+	mask := make([]bool, NumActions)
+	legal := make(map[int]game.Action)
+	for _, action := range state.LegalActions() {
+		if id, ok := ActionID(action); ok {
+			mask[id] = true
+			legal[id] = action
+		}
+	}
+	return mask, legal
+}
@@ -0,0 +1,78 @@
+package env
+
+import "sync"
+
+// Batch steps many environments concurrently, one goroutine per environment per step.
+// With AutoReset a finished environment is reset with the next unused seed, and the
+// observation returned for it is the first one of the new episode.
+type Batch struct {
+	Envs      []*Env
+	AutoReset bool
+
+	nextSeed int64
+}
+
+// NewBatch creates n environments with the same configuration
+func NewBatch(n int, cfg Config) *Batch {
+	b := &Batch{Envs: make([]*Env, n)}
+	for i := range b.Envs {
+		b.Envs[i] = New(cfg)
+	}
+	return b
+}
+
+// Reset resets environment i with seeds[i]. Automatic resets continue after the largest seed.
+func (b *Batch) Reset(seeds []int64) [][]float32 {
+	obs := make([][]float32, len(b.Envs))
+	b.parallel(func(i int) {
+		obs[i] = b.Envs[i].Reset(seeds[i])
+	})
+	b.nextSeed = 0
+	for _, seed := range seeds {
+		if seed >= b.nextSeed {
+			b.nextSeed = seed + 1
+		}
+	}
+	return obs
+}
+
+// Step plays actions[i] in environment i
+func (b *Batch) Step(actions []int) ([][]float32, []float64, []bool) {
+	obs := make([][]float32, len(b.Envs))
+	rewards := make([]float64, len(b.Envs))
+	dones := make([]bool, len(b.Envs))
+	b.parallel(func(i int) {
+		obs[i], rewards[i], dones[i] = b.Envs[i].Step(actions[i])
+	})
+
+	if b.AutoReset {
+		for i, done := range dones {
+			if done {
+				obs[i] = b.Envs[i].Reset(b.nextSeed)
+				b.nextSeed++
+			}
+		}
+	}
+	return obs, rewards, dones
+}
+
+// ActionMasks returns the legal-action mask of every environment
+func (b *Batch) ActionMasks() [][]bool {
+	masks := make([][]bool, len(b.Envs))
+	for i, e := range b.Envs {
+		masks[i] = e.ActionMask()
+	}
+	return masks
+}
+
+func (b *Batch) parallel(fn func(i int)) {
+	var wg sync.WaitGroup
+	for i := range b.Envs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
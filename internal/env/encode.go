@@ -0,0 +1,159 @@
+package env
+
+import "golem_century/internal/game"
+
+// Feature sizes
+const (
+	cardFeatures   = 13 // present, produce/upgrade/trade, input(4), output(4), turnUpgrade
+	marketFeatures = cardFeatures + 1 + 4
+	golemFeatures  = 1 + 4 + 1 // present, requirement(4), points
+	playerFeatures = 1 + 4 + 1 + 1 + 1 + 1 + 1
+	globalFeatures = 4                  // round, last round, action deck, point deck
+	coinFeatures   = game.CoinSlots * 2 // coins left, coin points
+)
+
+// ObservationSize is the length of every observation vector
+var ObservationSize = globalFeatures +
+	MaxHand*cardFeatures + // current player's hand
+	MaxHand*cardFeatures + // current player's played cards
+	MaxMarketCards*marketFeatures +
+	MaxPointCards*golemFeatures +
+	coinFeatures +
+	MaxPlayers*playerFeatures
+
+// Crystal counts and points are scaled to roughly [0, 1]
+const (
+	crystalScale    = 10.0
+	pointScale      = 100.0
+	golemPointScale = 20.0
+	roundScale      = 30.0
+	deckScale       = 50.0
+)
+
+// Encode returns a fixed-size numeric view of the game from the current player's seat.
+// Layout, in order:
+//
+//	global:  round, last round, action deck size, golem deck size
+//	hand:    MaxHand cards x [present, produce, upgrade, trade, input(4), output(4), turnUpgrade]
+//	played:  MaxHand cards, same features
+//	market:  MaxMarketCards x [card features, cost(yellow), deposits(4 colours)]
+//	golems:  MaxPointCards x [present, requirement(4), points]
+//	coins:   game.CoinSlots golem slots x [coins left, coin points]
+//	players: MaxPlayers seats starting with the current player x
+//	         [present, resources(4), points, final points, golems, hand size, rested]
+//
+// Opponents' hands are reduced to a count, so the encoding holds no hidden information.
+func Encode(state *game.GameState) []float32 {
+	obs := make([]float32, 0, ObservationSize)
+	market := state.Market
+
+	obs = append(obs,
+		float32(state.Round)/roundScale,
+		boolFeature(state.LastRound),
+		float32(len(market.ActionDeck))/deckScale,
+		float32(len(market.PointDeck))/deckScale,
+	)
+
+	current := state.GetCurrentPlayer()
+	obs = appendCards(obs, current.Hand, MaxHand)
+	obs = appendCards(obs, current.PlayedCards, MaxHand)
+
+	for i := 0; i < MaxMarketCards; i++ {
+		if i >= len(market.ActionCards) {
+			obs = append(obs, make([]float32, marketFeatures)...)
+			continue
+		}
+		card := market.ActionCards[i]
+		obs = appendCard(obs, card)
+		obs = append(obs, float32(market.GetActionCardCost(i).Yellow)/crystalScale)
+		deposits := game.NewResources()
+		for _, crystals := range card.Deposits {
+			for _, crystal := range crystals {
+				deposits.Add(crystal, 1)
+			}
+		}
+		obs = appendResources(obs, deposits)
+	}
+
+	for i := 0; i < MaxPointCards; i++ {
+		if i >= len(market.PointCards) {
+			obs = append(obs, make([]float32, golemFeatures)...)
+			continue
+		}
+		card := market.PointCards[i]
+		obs = append(obs, 1)
+		obs = appendResources(obs, card.Requirement)
+		obs = append(obs, float32(card.Points)/golemPointScale)
+	}
+
+	for i := 0; i < game.CoinSlots; i++ {
+		if coin := market.CoinOn(i); coin != nil {
+			obs = append(obs, float32(coin.Amount)/crystalScale, float32(coin.Points)/golemPointScale)
+		} else {
+			obs = append(obs, 0, 0)
+		}
+	}
+
+	// Seats are rotated so the current player always comes first
+	start := state.CurrentTurn % len(state.Players)
+	for i := 0; i < MaxPlayers; i++ {
+		if i >= len(state.Players) {
+			obs = append(obs, make([]float32, playerFeatures)...)
+			continue
+		}
+		p := state.Players[(start+i)%len(state.Players)]
+		obs = append(obs, 1)
+		obs = appendResources(obs, p.Resources)
+		obs = append(obs,
+			float32(p.GetPoints())/pointScale,
+			float32(p.GetFinalPoints())/pointScale,
+			float32(len(p.PointCards))/float32(MaxPointCards),
+			float32(len(p.Hand))/float32(MaxHand),
+			boolFeature(p.HasRested),
+		)
+	}
+
+	return obs
+}
+
+func appendCards(obs []float32, cards []*game.Card, slots int) []float32 {
+	for i := 0; i < slots; i++ {
+		if i < len(cards) {
+			obs = appendCard(obs, cards[i])
+		} else {
+			obs = append(obs, make([]float32, cardFeatures)...)
+		}
+	}
+	return obs
+}
+
+func appendCard(obs []float32, card *game.Card) []float32 {
+	obs = append(obs,
+		1,
+		boolFeature(card.ActionType == game.Produce),
+		boolFeature(card.ActionType == game.Upgrade),
+		boolFeature(card.ActionType == game.Trade),
+	)
+	obs = appendResources(obs, card.Input)
+	obs = appendResources(obs, card.Output)
+	return append(obs, float32(card.TurnUpgrade)/maxUpgrade)
+}
+
+func appendResources(obs []float32, r *game.Resources) []float32 {
+	if r == nil {
+		return append(obs, 0, 0, 0, 0)
+	}
+	return append(obs,
+		float32(r.Yellow)/crystalScale,
+		float32(r.Green)/crystalScale,
+		float32(r.Blue)/crystalScale,
+		float32(r.Pink)/crystalScale,
+	)
+}
+
+func boolFeature(b bool) float32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,156 @@
+// Package env wraps the game as a reinforcement-learning environment with a fixed-size
+// observation vector, a fixed action space with legal-action masks, and a batch runner
+// that steps many games concurrently.
+package env
+
+import (
+	"math/rand"
+
+	"golem_century/internal/game"
+)
+
+// DefaultMaxTurns caps episode length so a degenerate policy cannot run forever
+const DefaultMaxTurns = 500
+
+// Config describes an environment
+type Config struct {
+	NumPlayers int // 2-5 (default 2)
+	// AgentSeat is the seat the agent plays (1-based). Other seats are played by
+	// Opponent. 0 means self-play: the agent chooses for every seat.
+	AgentSeat int
+	Opponent  func(rng *rand.Rand) game.Bot // Default game.NewAIPlayer
+	MaxTurns  int                           // Default DefaultMaxTurns
+	WinReward float64                       // Added to the final reward when the agent's seat wins
+}
+
+// Env is one game. Observations are always from the seat about to act.
+//
+// Step applies the agent's action and, with an AgentSeat, plays opponents until it is
+// the agent's turn again. The reward is the change in the acting seat's final points
+// (golems, coins and non-yellow crystals) since its previous turn, plus WinReward at
+// the end if that seat finished first. An illegal action ID is played as a rest,
+// the same rule the bot API applies.
+type Env struct {
+	cfg       Config
+	state     *game.GameState
+	opponents map[int]game.Bot
+	mask      []bool
+	legal     map[int]game.Action
+	scores    map[int]int // Seat -> final points after its last turn
+	done      bool
+}
+
+// New creates an environment; call Reset before stepping
+func New(cfg Config) *Env {
+	if cfg.NumPlayers == 0 {
+		cfg.NumPlayers = 2
+	}
+	if cfg.MaxTurns == 0 {
+		cfg.MaxTurns = DefaultMaxTurns
+	}
+	if cfg.Opponent == nil {
+		cfg.Opponent = func(rng *rand.Rand) game.Bot { return game.NewAIPlayer(rng) }
+	}
+	return &Env{cfg: cfg}
+}
+
+// Reset starts a new game from seed and returns the first observation
+func (e *Env) Reset(seed int64) []float32 {
+	e.state = game.NewGameState(e.cfg.NumPlayers, seed)
+	e.opponents = make(map[int]game.Bot)
+	e.scores = make(map[int]int)
+	e.done = false
+	for _, p := range e.state.Players {
+		e.scores[p.ID] = p.GetFinalPoints()
+		if e.cfg.AgentSeat != 0 && p.ID != e.cfg.AgentSeat {
+			e.opponents[p.ID] = e.cfg.Opponent(e.state.RNG)
+			p.IsAI = true
+		}
+	}
+	e.playOpponents()
+	e.refresh()
+	return Encode(e.state)
+}
+
+// Step plays actionID for the current seat and returns the next observation,
+// the acting seat's reward and whether the episode is over
+func (e *Env) Step(actionID int) ([]float32, float64, bool) {
+	if e.done {
+		return Encode(e.state), 0, true
+	}
+
+	seat := e.state.GetCurrentPlayer().ID
+	action, ok := e.legal[actionID]
+	if !ok {
+		action = game.Action{Type: game.Rest}
+	}
+	e.play(action)
+	e.playOpponents()
+	e.refresh()
+
+	player := e.state.Players[seat-1]
+	reward := float64(player.GetFinalPoints() - e.scores[seat])
+	e.scores[seat] = player.GetFinalPoints()
+	if e.done && e.state.Standings()[0].GetFinalPoints() == player.GetFinalPoints() {
+		reward += e.cfg.WinReward
+	}
+	return Encode(e.state), reward, e.done
+}
+
+// ActionMask marks the legal action IDs for the seat about to act
+func (e *Env) ActionMask() []bool {
+	return e.mask
+}
+
+// Seat returns the seat about to act (1-based)
+func (e *Env) Seat() int {
+	return e.state.GetCurrentPlayer().ID
+}
+
+// Done reports whether the episode is over
+func (e *Env) Done() bool {
+	return e.done
+}
+
+// State exposes the underlying game, e.g. for logging. Do not modify it.
+func (e *Env) State() *game.GameState {
+	return e.state
+}
+
+// play applies one turn, resting if the game rejects the action
+func (e *Env) play(action game.Action) {
+	if err := e.state.ExecuteAction(action); err != nil {
+		e.state.ExecuteAction(game.Action{Type: game.Rest})
+	}
+	e.state.CheckGameOver()
+	if !e.state.GameOver {
+		e.state.NextTurn()
+	}
+}
+
+// playOpponents plays opponent seats until it is the agent's turn or the game ends
+func (e *Env) playOpponents() {
+	for !e.over() {
+		player := e.state.GetCurrentPlayer()
+		bot, ok := e.opponents[player.ID]
+		if !ok {
+			return
+		}
+		e.play(bot.ChooseAction(player, e.state.Market, e.state))
+	}
+}
+
+// refresh updates the done flag and the legal actions for the next seat
+func (e *Env) refresh() {
+	e.done = e.over()
+	if e.done {
+		e.mask = make([]bool, NumActions)
+		e.legal = nil
+		return
+	}
+	e.mask, e.legal = legalMask(e.state)
+}
+
+func (e *Env) over() bool {
+	return e.state.GameOver || e.state.CurrentTurn >= e.cfg.MaxTurns
+}
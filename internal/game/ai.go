@@ -2,6 +2,11 @@ package game
 
 import "math/rand"
 
+// Bot chooses actions for a computer-controlled seat
+type Bot interface {
+	ChooseAction(player *Player, market *Market, gameState *GameState) Action
+}
+
 // AIPlayer represents AI decision-making logic
 type AIPlayer struct {
 	rng *rand.Rand
@@ -23,11 +28,8 @@ func (ai *AIPlayer) ChooseAction(player *Player, market *Market, gameState *Game
 	}
 
 	// Priority 2: Play a card if possible (prefer production/upgrade)
-	if playableCard := ai.findPlayableCard(player); playableCard >= 0 {
-		return Action{
-			Type:     PlayCard,
-			CardIndex: playableCard,
-		}
+	if action, ok := ai.findPlayableCard(player, market); ok {
+		return action
 	}
 
 	// Priority 3: Acquire a cheap action card if we have resources
@@ -48,32 +50,40 @@ func (ai *AIPlayer) ChooseAction(player *Player, market *Market, gameState *Game
 }
 
 // findPlayableCard finds a playable card in hand (prefers production, then upgrade, then trade)
-func (ai *AIPlayer) findPlayableCard(player *Player) int {
+func (ai *AIPlayer) findPlayableCard(player *Player, market *Market) (Action, bool) {
 	// First pass: look for production cards
 	for i, card := range player.Hand {
 		if card.Type == ActionCard && card.ActionType == Produce {
 			if card.CanPlay(player, Action{Type: PlayCard, CardIndex: i}) {
-				return i
+				return Action{Type: PlayCard, CardIndex: i}, true
 			}
 		}
 	}
-	// Second pass: look for upgrade cards
+	// Second pass: look for upgrade cards, upgrading toward the nearest golem
 	for i, card := range player.Hand {
 		if card.Type == ActionCard && card.ActionType == Upgrade {
-			if card.CanPlay(player, Action{Type: PlayCard, CardIndex: i}) {
-				return i
+			if action, ok := PlanUpgrade(player, card, i, nearestRequirement(player, market)); ok {
+				return action, true
 			}
 		}
 	}
-	// Third pass: look for trade cards
+	// Third pass: look for trade cards, trading as many times as possible
 	for i, card := range player.Hand {
 		if card.Type == ActionCard && card.ActionType == Trade {
-			if card.CanPlay(player, Action{Type: PlayCard, CardIndex: i}) {
-				return i
+			if multiplier := MaxTradeMultiplier(player.Resources, card); multiplier > 0 {
+				return Action{Type: PlayCard, CardIndex: i, Multiplier: multiplier}, true
 			}
 		}
 	}
-	return -1
+	return Action{}, false
+}
+
+// nearestRequirement returns the requirement of the market golem fewest upgrade steps away
+func nearestRequirement(player *Player, market *Market) *Resources {
+	if golem, _ := nearestGolem(market, player.Resources); golem != nil {
+		return golem.Requirement
+	}
+	return nil
 }
 
 // findAffordableCard finds the cheapest affordable card in the market
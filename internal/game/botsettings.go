@@ -0,0 +1,159 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Difficulty names how strongly a computer player plays
+type Difficulty string
+
+const (
+	Beginner Difficulty = "beginner"
+	Easy     Difficulty = "easy"
+	Medium   Difficulty = "medium"
+	Hard     Difficulty = "hard"
+	Expert   Difficulty = "expert"
+)
+
+// Difficulties lists the difficulty levels from weakest to strongest
+var Difficulties = []Difficulty{Beginner, Easy, Medium, Hard, Expert}
+
+// difficultyLevel is how a difficulty plays: search depth, score noise and mistake rate
+type difficultyLevel struct {
+	depth    int
+	noise    float64
+	mistakes float64
+}
+
+var difficultyLevels = map[Difficulty]difficultyLevel{
+	Beginner: {depth: 1, noise: 4.0, mistakes: 0.30},
+	Easy:     {depth: 1, noise: 2.0, mistakes: 0.15},
+	Medium:   {depth: 2, noise: 1.0, mistakes: 0.05},
+	Hard:     {depth: 2, noise: 0.25},
+	Expert:   {depth: 3},
+}
+
+// Personality names a computer player's style
+type Personality string
+
+const (
+	Balanced      Personality = "balanced"
+	EngineBuilder Personality = "engine-builder" // Favours acquiring trade cards
+	Rusher        Personality = "rusher"         // Goes for cheap golems early
+	CoinChaser    Personality = "coin-chaser"    // Races for golems that carry coins
+)
+
+// Personalities lists the available personalities
+var Personalities = []Personality{Balanced, EngineBuilder, Rusher, CoinChaser}
+
+// Apply returns weights adjusted to the personality
+func (p Personality) Apply(w Weights) Weights {
+	switch p {
+	case EngineBuilder:
+		w.Engine *= 1.5
+		w.TradeCards += 0.2
+	case Rusher:
+		w.StepDecay *= 0.7
+		w.Engine *= 0.5
+		w.Discount = 0.85
+	case CoinChaser:
+		w.Coins *= 4
+	}
+	return w
+}
+
+// ParseDifficulty parses a difficulty name; empty means Medium
+func ParseDifficulty(name string) (Difficulty, error) {
+	if name == "" {
+		return Medium, nil
+	}
+	d := Difficulty(strings.ToLower(name))
+	if _, ok := difficultyLevels[d]; !ok {
+		return "", fmt.Errorf("unknown difficulty %q (want one of %s)", name, joinNames(Difficulties))
+	}
+	return d, nil
+}
+
+// ParsePersonality parses a personality name; empty means Balanced
+func ParsePersonality(name string) (Personality, error) {
+	if name == "" {
+		return Balanced, nil
+	}
+	p := Personality(strings.ToLower(name))
+	for _, known := range Personalities {
+		if p == known {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown personality %q (want one of %s)", name, joinNames(Personalities))
+}
+
+// BotSettings selects a computer player's difficulty and personality
+type BotSettings struct {
+	Difficulty  Difficulty  `json:"difficulty,omitempty"`
+	Personality Personality `json:"personality,omitempty"`
+}
+
+// ParseBotSettings parses "difficulty[:personality]", e.g. "hard:rusher"
+func ParseBotSettings(spec string) (BotSettings, error) {
+	difficulty, personality, _ := strings.Cut(spec, ":")
+	return BotSettings{Difficulty: Difficulty(difficulty), Personality: Personality(personality)}.Normalize()
+}
+
+// Normalize fills in defaults and validates the names
+func (s BotSettings) Normalize() (BotSettings, error) {
+	difficulty, err := ParseDifficulty(string(s.Difficulty))
+	if err != nil {
+		return s, err
+	}
+	personality, err := ParsePersonality(string(s.Personality))
+	if err != nil {
+		return s, err
+	}
+	return BotSettings{Difficulty: difficulty, Personality: personality}, nil
+}
+
+// Name returns a display name such as "Hard Rusher Bot"
+func (s BotSettings) Name() string {
+	parts := []string{titleCase(string(s.Difficulty))}
+	if s.Personality != "" && s.Personality != Balanced {
+		parts = append(parts, titleCase(string(s.Personality)))
+	}
+	return strings.Join(append(parts, "Bot"), " ")
+}
+
+// NewBot creates a lookahead bot for the settings. Personalities adjust the base
+// weights (usually DefaultWeights). rng drives noise and mistakes.
+func NewBot(settings BotSettings, base Weights, rng *rand.Rand) (*LookaheadBot, error) {
+	settings, err := settings.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	level := difficultyLevels[settings.Difficulty]
+	bot := NewLookaheadBot(settings.Personality.Apply(base), level.depth)
+	bot.Noise = level.noise
+	bot.Mistakes = level.mistakes
+	bot.rng = rng
+	return bot, nil
+}
+
+func joinNames[T ~string](names []T) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = string(name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// titleCase turns "engine-builder" into "Engine-Builder"
+func titleCase(s string) string {
+	b := []byte(s)
+	for i := range b {
+		if (i == 0 || b[i-1] == '-') && b[i] >= 'a' && b[i] <= 'z' {
+			b[i] -= 'a' - 'A'
+		}
+	}
+	return string(b)
+}
@@ -75,7 +75,8 @@ func (c *Card) CanPlay(player *Player, action Action) bool {
 			return false
 		}
 
-		if !player.Resources.HasAll(action.InputResources, action.Multiplier) {
+		// Upgrades are played once; the multiplier only applies to trades
+		if !player.Resources.HasAll(action.InputResources, 1) {
 			return false
 		}
 
@@ -84,6 +85,11 @@ func (c *Card) CanPlay(player *Player, action Action) bool {
 		}
 
 	} else {
+		// A trade repeats only as often as the player can pay, which also keeps
+		// the multiplied input from overflowing
+		if c.ActionType == Trade && action.Multiplier > MaxTradeMultiplier(player.Resources, c) {
+			return false
+		}
 		// Check if player has required input resources
 		if c.Input != nil && !player.Resources.HasAll(c.Input, action.Multiplier) {
 			return false
@@ -715,11 +721,12 @@ func CreateDefaultPointCards() []*Card {
 	return cards
 }
 
-// CreateCoinCards creates coin cards
+// CreateCoinCards creates the coin piles in slot order: copper above the first
+// golem, silver above the second
 func CreateCoinCards() []*Card {
 	return []*Card{
+		CreateCardFromName("coin_3", 201), // Copper coin = 3 points
 		CreateCardFromName("coin_1", 200), // Silver coin = 1 point
-		CreateCardFromName("coin_3", 201), // Bronze coin = 3 points
 	}
 }
 
@@ -0,0 +1,60 @@
+package game
+
+// Clone returns a copy of the game that can be played forward without touching the
+// original. Cards are shared, except coins, cards carrying deposits and the market's
+// action row, which the rules modify in place. The copy has no RNG and prints no debug output.
+func (gs *GameState) Clone() *GameState {
+	cards := make(map[*Card]*Card)
+	copyCard := func(card *Card, always bool) *Card {
+		if !always && card.Type != CoinCard && len(card.Deposits) == 0 {
+			return card
+		}
+		if c, ok := cards[card]; ok {
+			return c
+		}
+		c := *card
+		c.Deposits = make(map[int][]CrystalType, len(card.Deposits))
+		for position, crystals := range card.Deposits {
+			c.Deposits[position] = append([]CrystalType(nil), crystals...)
+		}
+		cards[card] = &c
+		return &c
+	}
+	copyCards := func(list []*Card) []*Card {
+		out := make([]*Card, len(list))
+		for i, card := range list {
+			out[i] = copyCard(card, false)
+		}
+		return out
+	}
+
+	market := *gs.Market
+	// Deposits are made on the action row, so its cards are never shared
+	market.ActionCards = make([]*Card, len(gs.Market.ActionCards))
+	for i, card := range gs.Market.ActionCards {
+		market.ActionCards[i] = copyCard(card, true)
+	}
+	market.PointCards = copyCards(gs.Market.PointCards)
+	market.ActionDeck = copyCards(gs.Market.ActionDeck)
+	market.PointDeck = copyCards(gs.Market.PointDeck)
+	market.Coins = copyCards(gs.Market.Coins)
+
+	clone := *gs
+	clone.Market = &market
+	clone.Players = make([]*Player, len(gs.Players))
+	clone.RNG = nil
+	clone.quiet = true
+	for i, player := range gs.Players {
+		p := *player
+		p.Resources = player.Resources.Copy()
+		p.Hand = copyCards(player.Hand)
+		p.PlayedCards = copyCards(player.PlayedCards)
+		p.PointCards = copyCards(player.PointCards)
+		p.Coins = copyCards(player.Coins)
+		clone.Players[i] = &p
+		if gs.Winner == player {
+			clone.Winner = &p
+		}
+	}
+	return &clone
+}
@@ -8,7 +8,15 @@ import (
 // Engine manages the game flow and turn execution
 type Engine struct {
 	GameState *GameState
-	AI        *AIPlayer
+	AI        Bot         // Plays every seat without its own bot
+	Bots      map[int]Bot // Bots for individual seats, by player ID
+	// Optional extra end condition checked after every turn, e.g. a challenge's
+	// goal; it returns true once it has ended the game
+	CheckEnd func(*GameState) bool
+	// Quiet prints one line per action instead of the full state every turn and
+	// hides debug output, for interactive play where seats render their own board
+	Quiet   bool
+	History []string // "Name: action" for every action taken, in order
 }
 
 // NewEngine creates a new game engine
@@ -18,6 +26,7 @@ func NewEngine(numPlayers int, seed int64) *Engine {
 	return &Engine{
 		GameState: gameState,
 		AI:        ai,
+		Bots:      make(map[int]Bot),
 	}
 }
 
@@ -28,20 +37,34 @@ func (e *Engine) Run() {
 
 	maxTurns := 1000 // Safety limit
 	turnCount := 0
+	if e.Quiet {
+		e.GameState.quiet = true
+	}
 
 	for !e.GameState.GameOver && turnCount < maxTurns {
 		turnCount++
 		player := e.GameState.GetCurrentPlayer()
 
 		// Print current state
-		e.GameState.PrintState()
+		if !e.Quiet {
+			e.GameState.PrintState()
+		}
 
-		// Get action from AI
-		action := e.AI.ChooseAction(player, e.GameState.Market, e.GameState)
+		// Get action from the seat's bot
+		bot, ok := e.Bots[player.ID]
+		if !ok {
+			bot = e.AI
+		}
+		action := bot.ChooseAction(player, e.GameState.Market, e.GameState)
 
 		// Execute action
-		actionStr := e.getActionString(action, player)
-		fmt.Printf("\n>>> Action: %s\n", actionStr)
+		actionStr := e.GameState.DescribeAction(action)
+		e.Record(player, actionStr)
+		if e.Quiet {
+			fmt.Printf("%s: %s\n", player.Name, actionStr)
+		} else {
+			fmt.Printf("\n>>> Action: %s\n", actionStr)
+		}
 
 		if err := e.GameState.ExecuteAction(action); err != nil {
 			fmt.Printf("ERROR: %v\n", err)
@@ -51,6 +74,9 @@ func (e *Engine) Run() {
 
 		// Check for game over
 		e.GameState.CheckGameOver()
+		if e.CheckEnd != nil && e.CheckEnd(e.GameState) {
+			e.GameState.GameOver = true
+		}
 
 		// Advance to next turn
 		if !e.GameState.GameOver {
@@ -69,32 +95,9 @@ func (e *Engine) Run() {
 	e.GameState.PrintFinalResults()
 }
 
-// getActionString returns a string representation of an action
-func (e *Engine) getActionString(action Action, player *Player) string {
-	switch action.Type {
-	case PlayCard:
-		if action.CardIndex >= 0 && action.CardIndex < len(player.Hand) {
-			return fmt.Sprintf("Play Card: %s", player.Hand[action.CardIndex].Name)
-		}
-		return "Play Card (invalid index)"
-	case AcquireCard:
-		if action.CardIndex >= 0 && action.CardIndex < len(e.GameState.Market.ActionCards) {
-			card := e.GameState.Market.ActionCards[action.CardIndex]
-			cost := e.GameState.Market.GetActionCardCost(action.CardIndex)
-			return fmt.Sprintf("Acquire Card: %s (Cost: %s)", card.Name, cost.String())
-		}
-		return "Acquire Card (invalid index)"
-	case ClaimPointCard:
-		if action.CardIndex >= 0 && action.CardIndex < len(e.GameState.Market.PointCards) {
-			card := e.GameState.Market.PointCards[action.CardIndex]
-			return fmt.Sprintf("Claim Point Card: %s (%d points)", card.Name, card.Points)
-		}
-		return "Claim Point Card (invalid index)"
-	case Rest:
-		return "Rest (return all played cards to hand)"
-	default:
-		return "Unknown Action"
-	}
+// Record adds an action by player to the history
+func (e *Engine) Record(player *Player, description string) {
+	e.History = append(e.History, fmt.Sprintf("%s: %s", player.Name, description))
 }
 
 // GetGameState returns the current game state
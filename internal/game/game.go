@@ -3,6 +3,7 @@ package game
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 )
 
@@ -20,6 +21,46 @@ const (
 	CollectAllCrystals
 )
 
+// actionTypeNames are the wire names of action types, shared with the WebSocket protocol
+var actionTypeNames = map[PlayerActionType]string{
+	PlayCard:           "playCard",
+	AcquireCard:        "acquireCard",
+	ClaimPointCard:     "claimPointCard",
+	Rest:               "rest",
+	DiscardCrystals:    "discardCrystals",
+	DepositCrystals:    "depositCrystals",
+	CollectCrystals:    "collectCrystals",
+	CollectAllCrystals: "collectAllCrystals",
+}
+
+// String returns the wire name of the action type
+func (t PlayerActionType) String() string {
+	if name, ok := actionTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("PlayerActionType(%d)", int(t))
+}
+
+// MarshalText encodes the action type by name
+func (t PlayerActionType) MarshalText() ([]byte, error) {
+	name, ok := actionTypeNames[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown action type %d", int(t))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes an action type name
+func (t *PlayerActionType) UnmarshalText(text []byte) error {
+	for actionType, name := range actionTypeNames {
+		if name == string(text) {
+			*t = actionType
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown action type %q", text)
+}
+
 // DepositDirection represents the direction for deposits (N- or N+)
 type DepositDirection int
 
@@ -30,16 +71,16 @@ const (
 
 // Action represents a player action
 type Action struct {
-	Type             PlayerActionType
-	CardIndex        int                   // Index in hand/market depending on action type
-	Multiplier       int                   // Multiplier for the trade action
-	InputResources   *Resources            // Input resources for upgrade
-	OutputResources  *Resources            // Output resources for upgrade
-	Discard          *Resources            // Crystals to discard (for DiscardCrystals action)
-	Deposits         map[int][]CrystalType // Position -> Array of Crystal types for deposit (for DepositCrystals, supports stacking)
-	TargetPosition   int                   // Target position for deposit (1-5)
-	DepositDirection DepositDirection      // Direction for deposits: N- (previous) or N+ (next)
-	CollectPositions []int                 // Positions to collect from (for CollectCrystals)
+	Type             PlayerActionType      `json:"type"`
+	CardIndex        int                   `json:"cardIndex"`                  // Index in hand/market depending on action type
+	Multiplier       int                   `json:"multiplier,omitempty"`       // Multiplier for the trade action
+	InputResources   *Resources            `json:"inputResources,omitempty"`   // Input resources for upgrade
+	OutputResources  *Resources            `json:"outputResources,omitempty"`  // Output resources for upgrade
+	Discard          *Resources            `json:"discard,omitempty"`          // Crystals to discard (for DiscardCrystals action)
+	Deposits         map[int][]CrystalType `json:"deposits,omitempty"`         // Position -> Array of Crystal types for deposit (for DepositCrystals, supports stacking)
+	TargetPosition   int                   `json:"targetPosition,omitempty"`   // Target position for deposit (1 to the market size)
+	DepositDirection DepositDirection      `json:"depositDirection,omitempty"` // Direction for deposits: N- (previous) or N+ (next)
+	CollectPositions []int                 `json:"positions,omitempty"`        // Positions to collect from (for CollectCrystals)
 }
 
 // GameState represents the current state of the game
@@ -51,55 +92,48 @@ type GameState struct {
 	GameOver    bool
 	Winner      *Player
 	LastRound   bool // Whether the last round is being played
+	GolemsToEnd int  // Golems a player claims to trigger the last round (0 means 5)
 	RNG         *rand.Rand
+
+	quiet bool // Suppresses debug output, for copies used by bots to look ahead
+}
+
+// Supported player counts
+const (
+	MinPlayers = 2
+	MaxPlayers = 5
+)
+
+// coinsPerPlayer is how many coins each coin pile holds per player
+const coinsPerPlayer = 2
+
+// marketSize returns how many action cards and golems the market shows.
+// Five players get a sixth action card so the row still has a choice on each turn.
+func marketSize(numPlayers int) (actionCards, pointCards int) {
+	if numPlayers >= 5 {
+		return 6, 5
+	}
+	return 5, 5
 }
 
-// NewGameState creates a new game state
+// NewGameState creates a new game state for MinPlayers to MaxPlayers players
+// with the standard setup (see GameSetup)
 func NewGameState(numPlayers int, seed int64) *GameState {
-	rng := rand.New(rand.NewSource(seed))
-
-	// Create players (all human by default)
-	players := make([]*Player, numPlayers)
-	for i := 0; i < numPlayers; i++ {
-		name := fmt.Sprintf("Player %d", i+1)
-		players[i] = NewPlayer(i+1, name, false)
-		// Give each player starting resources based on player position
-		// Player 1: 3 yellow
-		// Player 2: 4 yellow
-		// Player 3: 4 yellow
-		// Player 4: 3 yellow + 1 green
-		// Player 5: 3 yellow + 1 green
-		playerIndex := i + 1 // 1-based player index
-		switch playerIndex {
-		case 1:
-			players[i].Resources.Yellow = 3
-		case 2, 3:
-			players[i].Resources.Yellow = 4
-		case 4, 5:
-			players[i].Resources.Yellow = 3
-			players[i].Resources.Green = 1
-		default:
-			// For games with more than 5 players, default to 3 yellow
-			players[i].Resources.Yellow = 3
-		}
-		players[i].Hand = append(players[i].Hand, CreateInitialActionCards(i)...)
+	return GameSetup{NumPlayers: numPlayers, Seed: seed}.newGame()
+}
+
+// golemsToEnd returns how many golems trigger the last round
+func (gs *GameState) golemsToEnd() int {
+	if gs.GolemsToEnd > 0 {
+		return gs.GolemsToEnd
 	}
+	return defaultGolemsToEnd
+}
 
-	// Create market
-	actionCards := CreateDefaultActionCards()
-	pointCards := CreateDefaultPointCards()
-	coins := CreateCoinCards()
-	market := NewMarket(actionCards, pointCards, coins, 5, 5, rng)
-
-	return &GameState{
-		Players:     players,
-		Market:      market,
-		CurrentTurn: 0,
-		Round:       1,
-		GameOver:    false,
-		Winner:      nil,
-		LastRound:   false,
-		RNG:         rng,
+// debugf prints a debug line unless the state is a quiet copy
+func (gs *GameState) debugf(format string, args ...interface{}) {
+	if !gs.quiet {
+		fmt.Printf("[DEBUG] "+format, args...)
 	}
 }
 
@@ -123,7 +157,16 @@ func (gs *GameState) NextTurn() {
 // ExecuteAction executes a player action
 func (gs *GameState) ExecuteAction(action Action) error {
 	player := gs.GetCurrentPlayer()
+	before := player.Resources.Copy()
+	if err := gs.executeAction(player, action); err != nil {
+		return err
+	}
+	gs.recordAction(player, action, before)
+	return nil
+}
 
+// executeAction applies an action for player without bookkeeping
+func (gs *GameState) executeAction(player *Player, action Action) error {
 	switch action.Type {
 	case PlayCard:
 		if action.CardIndex < 0 || action.CardIndex >= len(player.Hand) {
@@ -154,14 +197,14 @@ func (gs *GameState) ExecuteAction(action Action) error {
 				requiredPosition := i + 1
 				if prevCard.Deposits == nil {
 					hasAllRequiredDeposits = false
-					fmt.Printf("[DEBUG] Card index %d (position %d) has no deposits map\n", i, requiredPosition)
+					gs.debugf("Card index %d (position %d) has no deposits map\n", i, requiredPosition)
 					break
 				}
 				// Check if this card has deposits at position i+1 (array must have at least one element)
 				depositArray, exists := prevCard.Deposits[requiredPosition]
 				if !exists || len(depositArray) == 0 {
 					hasAllRequiredDeposits = false
-					fmt.Printf("[DEBUG] Card index %d (position %d) missing required deposit at position %d\n", i, requiredPosition, requiredPosition)
+					gs.debugf("Card index %d (position %d) missing required deposit at position %d\n", i, requiredPosition, requiredPosition)
 					break
 				}
 			}
@@ -183,7 +226,7 @@ func (gs *GameState) ExecuteAction(action Action) error {
 			}
 			// Clear all deposits from target card
 			targetCard.Deposits = make(map[int][]CrystalType)
-			fmt.Printf("[DEBUG] Collected deposits from target card index %d: %d crystals\n",
+			gs.debugf("Collected deposits from target card index %d: %d crystals\n",
 				action.CardIndex, collectedFromTarget.Total())
 		}
 
@@ -196,22 +239,22 @@ func (gs *GameState) ExecuteAction(action Action) error {
 		// Add collected crystals from target card to player
 		if collectedFromTarget.Total() > 0 {
 			player.Resources.AddAll(collectedFromTarget, 1)
-			fmt.Printf("[DEBUG] Added %d crystals from target card deposits to player\n", collectedFromTarget.Total())
+			gs.debugf("Added %d crystals from target card deposits to player\n", collectedFromTarget.Total())
 		}
 
 		// If card index is 0 (position 1) OR player has deposited on ALL previous cards, acquire is FREE (no cost)
 		// Otherwise, player must pay the normal cost
 		if action.CardIndex == 0 || hasAllRequiredDeposits {
 			if action.CardIndex == 0 {
-				fmt.Printf("[DEBUG] Card index 0 (position 1) is always FREE\n")
+				gs.debugf("Card index 0 (position 1) is always FREE\n")
 			} else {
-				fmt.Printf("[DEBUG] Player has deposited on all previous cards, acquiring card index %d for FREE\n", action.CardIndex)
+				gs.debugf("Player has deposited on all previous cards, acquiring card index %d for FREE\n", action.CardIndex)
 			}
 			// No cost, just add card
 			player.AddCard(card)
 		} else {
 			// Missing required deposits, must pay the normal card cost
-			fmt.Printf("[DEBUG] Missing required deposits on previous cards, must pay cost %s\n", cost.String())
+			gs.debugf("Missing required deposits on previous cards, must pay cost %s\n", cost.String())
 			if !player.Resources.HasAll(cost, 1) {
 				// Put card back if acquisition failed
 				gs.Market.ActionCards = append(gs.Market.ActionCards, card)
@@ -242,14 +285,13 @@ func (gs *GameState) ExecuteAction(action Action) error {
 		gs.Market.PointCards = append(gs.Market.PointCards[:action.CardIndex], gs.Market.PointCards[action.CardIndex+1:]...)
 		gs.Market.RefillPointCards()
 
-		// check bonus coin if player has claimed point card
-		if action.CardIndex <= 1 && gs.Market.Coins[action.CardIndex].Amount > 0 {
-			player.Coins = append(player.Coins, gs.Market.Coins[action.CardIndex])
-			gs.Market.Coins[action.CardIndex].Amount--
+		// Golems on the first slots come with a coin
+		if coin := gs.Market.TakeCoin(action.CardIndex); coin != nil {
+			player.Coins = append(player.Coins, coin)
 		}
 
 		// Check win condition
-		if player.CheckLastRound() {
+		if len(player.PointCards) >= gs.golemsToEnd() {
 			gs.LastRound = true
 		}
 
@@ -291,7 +333,7 @@ func (gs *GameState) ExecuteAction(action Action) error {
 		if action.Deposits == nil || len(action.Deposits) == 0 {
 			return fmt.Errorf("no deposits specified")
 		}
-		if action.TargetPosition < 1 || action.TargetPosition > 5 {
+		if action.TargetPosition < 1 || action.TargetPosition > len(gs.Market.ActionCards) {
 			return fmt.Errorf("invalid target position")
 		}
 
@@ -328,10 +370,10 @@ func (gs *GameState) ExecuteAction(action Action) error {
 				card.Deposits[position] = make([]CrystalType, 0)
 			}
 			card.Deposits[position] = append(card.Deposits[position], crystalType)
-			fmt.Printf("[DEBUG] Deposited %s to card index %d (position %d): %s (total at position: %d)\n",
-				crystalType, i, position, card.Name, len(card.Deposits[position]))
+			gs.debugf("Deposited %s to card index %d (position %d): %s (total at position: %d)\n",
+				CrystalTypeNames[crystalType], i, position, card.Name, len(card.Deposits[position]))
 		}
-		fmt.Printf("[DEBUG] Deposit complete: deposited to %d cards (positions 1 to %d), crystals deducted\n", marketIndex, marketIndex)
+		gs.debugf("Deposit complete: deposited to %d cards (positions 1 to %d), crystals deducted\n", marketIndex, marketIndex)
 
 	case CollectCrystals:
 		// Collect crystals from a card (from hand or market)
@@ -403,9 +445,10 @@ func (gs *GameState) ExecuteAction(action Action) error {
 	return nil
 }
 
-// CheckGameOver checks if the game is over
+// CheckGameOver checks if the game is over. Once the last round has been
+// triggered it is played out, so every player gets the same number of turns.
 func (gs *GameState) CheckGameOver() {
-	if gs.LastRound {
+	if gs.LastRound && gs.CurrentTurn%len(gs.Players) == len(gs.Players)-1 {
 		gs.GameOver = true
 		for _, player := range gs.Players {
 			if gs.Winner == nil || player.GetFinalPoints() > gs.Winner.GetFinalPoints() {
@@ -415,6 +458,17 @@ func (gs *GameState) CheckGameOver() {
 	}
 }
 
+// Standings returns the players ordered by final points, best first.
+// Ties keep seat order.
+func (gs *GameState) Standings() []*Player {
+	players := make([]*Player, len(gs.Players))
+	copy(players, gs.Players)
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].GetFinalPoints() > players[j].GetFinalPoints()
+	})
+	return players
+}
+
 // PrintState prints the current game state
 func (gs *GameState) PrintState() {
 	fmt.Println("\n" + "=" + strings.Repeat("=", 78))
@@ -462,7 +516,7 @@ func (gs *GameState) PrintFinalResults() {
 	for i, player := range players {
 		rank := i + 1
 		winnerMark := ""
-		if player.ID == gs.Winner.ID {
+		if gs.Winner != nil && player.ID == gs.Winner.ID {
 			winnerMark = " 🏆 WINNER"
 		}
 		fmt.Printf("\n%d. %s - %d Points (%d Point Cards)%s\n",
@@ -0,0 +1,199 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+var setupTests = []struct {
+	players     int
+	crystals    []Resources // Starting crystals by seat
+	actionCards int         // Market action cards on show
+}{
+	{2, []Resources{{Yellow: 3}, {Yellow: 4}}, 5},
+	{3, []Resources{{Yellow: 3}, {Yellow: 4}, {Yellow: 4}}, 5},
+	{4, []Resources{{Yellow: 3}, {Yellow: 4}, {Yellow: 4}, {Yellow: 3, Green: 1}}, 5},
+	{5, []Resources{{Yellow: 3}, {Yellow: 4}, {Yellow: 4}, {Yellow: 3, Green: 1}, {Yellow: 3, Green: 1}}, 6},
+}
+
+func TestNewGameState(t *testing.T) {
+	for _, tt := range setupTests {
+		gs := NewGameState(tt.players, 1)
+		if len(gs.Players) != tt.players {
+			t.Fatalf("%d players: got %d seats", tt.players, len(gs.Players))
+		}
+		for i, player := range gs.Players {
+			if *player.Resources != tt.crystals[i] {
+				t.Errorf("%d players: seat %d starts with %+v, want %+v", tt.players, i+1, *player.Resources, tt.crystals[i])
+			}
+		}
+		if len(gs.Market.Coins) != CoinSlots {
+			t.Errorf("%d players: %d coin piles, want %d", tt.players, len(gs.Market.Coins), CoinSlots)
+		}
+		for i, pile := range gs.Market.Coins {
+			if pile.Amount != 2*tt.players {
+				t.Errorf("%d players: coin pile %d holds %d, want %d", tt.players, i, pile.Amount, 2*tt.players)
+			}
+		}
+		if len(gs.Market.ActionCards) != tt.actionCards {
+			t.Errorf("%d players: %d action cards on show, want %d", tt.players, len(gs.Market.ActionCards), tt.actionCards)
+		}
+		if len(gs.Market.PointCards) != 5 {
+			t.Errorf("%d players: %d golems on show, want 5", tt.players, len(gs.Market.PointCards))
+		}
+	}
+}
+
+// The last round is played out, whichever seat triggers it
+func TestCheckGameOverPlaysOutLastRound(t *testing.T) {
+	for _, tt := range setupTests {
+		for trigger := 0; trigger < tt.players; trigger++ {
+			gs := NewGameState(tt.players, 1)
+			for i := 0; i < trigger; i++ {
+				gs.NextTurn()
+			}
+			gs.LastRound = true
+			for seat := trigger; seat < tt.players-1; seat++ {
+				gs.CheckGameOver()
+				if gs.GameOver {
+					t.Fatalf("%d players, triggered by seat %d: game ended after seat %d", tt.players, trigger+1, seat+1)
+				}
+				gs.NextTurn()
+			}
+			gs.CheckGameOver()
+			if !gs.GameOver {
+				t.Errorf("%d players, triggered by seat %d: game not over after the last seat", tt.players, trigger+1)
+			}
+			if gs.Winner == nil {
+				t.Errorf("%d players, triggered by seat %d: no winner", tt.players, trigger+1)
+			}
+		}
+	}
+}
+
+// Every market card can be bought with deposits, including a five-player game's sixth
+func TestDepositOnLastMarketCard(t *testing.T) {
+	for _, tt := range setupTests {
+		gs := NewGameState(tt.players, 1)
+		gs.quiet = true
+		player := gs.GetCurrentPlayer()
+		last := len(gs.Market.ActionCards) - 1
+		player.Resources = &Resources{Yellow: last}
+		deposits := make(map[int][]CrystalType, last)
+		for position := 1; position <= last; position++ {
+			deposits[position] = []CrystalType{Yellow}
+		}
+		card := gs.Market.ActionCards[last]
+
+		err := gs.ExecuteAction(Action{
+			Type:           DepositCrystals,
+			CardIndex:      len(player.Hand) + last,
+			Deposits:       deposits,
+			TargetPosition: last + 1,
+		})
+		if err != nil {
+			t.Fatalf("%d players: deposit for market card %d: %v", tt.players, last, err)
+		}
+		if err := gs.ExecuteAction(Action{Type: AcquireCard, CardIndex: last}); err != nil {
+			t.Fatalf("%d players: acquire market card %d: %v", tt.players, last, err)
+		}
+		if got := player.Hand[len(player.Hand)-1]; got != card {
+			t.Errorf("%d players: acquired %s, want %s", tt.players, got.Name, card.Name)
+		}
+	}
+}
+
+func TestTakeCoin(t *testing.T) {
+	tests := []struct {
+		amounts []int    // Coins set on each pile
+		take    []int    // Slots taken from, in order
+		want    []string // Coins taken; "" when the slot had none
+		left    []int    // Coins left on each remaining pile
+	}{
+		{[]int{2, 2}, []int{0, 0, 0, 0, 0}, []string{"coin_3", "coin_3", "coin_1", "coin_1", ""}, []int{}},
+		{[]int{1, 3}, []int{1, 0, 1}, []string{"coin_1", "coin_3", ""}, []int{2}},
+		{[]int{3, 0}, []int{1, 0}, []string{"", "coin_3"}, []int{2}},
+		{[]int{0, 1}, []int{0, 0}, []string{"coin_1", ""}, []int{}},
+		{[]int{10, 10}, []int{1, 1}, []string{"coin_1", "coin_1"}, []int{10, 8}},
+	}
+	for _, tt := range tests {
+		market := &Market{Coins: CreateCoinCards()}
+		market.SetCoins(tt.amounts)
+		seen := make(map[int]bool)
+		for i, slot := range tt.take {
+			coin := market.TakeCoin(slot)
+			switch {
+			case coin == nil && tt.want[i] != "":
+				t.Errorf("%v: take %d from slot %d: got none, want %s", tt.amounts, i+1, slot, tt.want[i])
+			case coin != nil && coin.Name != tt.want[i]:
+				t.Errorf("%v: take %d from slot %d: got %s, want %q", tt.amounts, i+1, slot, coin.Name, tt.want[i])
+			case coin != nil:
+				if coin.Amount != 1 || seen[coin.ID] {
+					t.Errorf("%v: take %d: coin %d (amount %d) is not a card of its own", tt.amounts, i+1, coin.ID, coin.Amount)
+				}
+				seen[coin.ID] = true
+			}
+		}
+		left := make([]int, len(market.Coins))
+		for i, pile := range market.Coins {
+			left[i] = pile.Amount
+		}
+		if fmt.Sprint(left) != fmt.Sprint(tt.left) {
+			t.Errorf("%v: piles left %v, want %v", tt.amounts, left, tt.left)
+		}
+	}
+}
+
+// Claimed coins are separate cards, not the market's pile
+func TestClaimAwardsOwnCoin(t *testing.T) {
+	gs := NewGameState(2, 1)
+	gs.quiet = true
+	pile := gs.Market.Coins[0]
+	for turn := 0; turn < 2; turn++ {
+		player := gs.GetCurrentPlayer()
+		player.Resources = &Resources{Yellow: 5, Green: 5, Blue: 5, Pink: 5}
+		if err := gs.ExecuteAction(Action{Type: ClaimPointCard, CardIndex: 0}); err != nil {
+			t.Fatal(err)
+		}
+		if len(player.Coins) != 1 || player.Coins[0] == pile || player.Coins[0].Amount != 1 {
+			t.Fatalf("seat %d holds %v after claiming the first golem", player.ID, player.Coins)
+		}
+		gs.NextTurn()
+	}
+	if a, b := gs.Players[0].Coins[0], gs.Players[1].Coins[0]; a == b || a.ID == b.ID {
+		t.Errorf("both seats hold coin %d", a.ID)
+	}
+	if pile.Amount != 2 {
+		t.Errorf("copper pile holds %d, want 2", pile.Amount)
+	}
+}
+
+func TestTradeMultiplier(t *testing.T) {
+	card := CreateCardFromName("trade_0002_0020", 1) // YY -> GG
+	tests := []struct {
+		yellow     int
+		multiplier int
+		canPlay    bool
+		max        int
+	}{
+		{2, 1, true, 1},
+		{2, 3, false, 1}, // Crystals for one trade only
+		{6, 3, true, 3},
+		{7, 4, false, 3},
+		{1, 1, false, 0},
+		{2, 1 << 62, false, 1}, // The multiplied input would overflow
+	}
+	for _, tt := range tests {
+		player := NewPlayer(1, "Ann", false)
+		player.Resources = &Resources{Yellow: tt.yellow}
+		if got := MaxTradeMultiplier(player.Resources, card); got != tt.max {
+			t.Errorf("%d yellow: max multiplier %d, want %d", tt.yellow, got, tt.max)
+		}
+		if got := card.CanPlay(player, Action{Type: PlayCard, Multiplier: tt.multiplier}); got != tt.canPlay {
+			t.Errorf("%d yellow, x%d: CanPlay %v, want %v", tt.yellow, tt.multiplier, got, tt.canPlay)
+		}
+		if got := player.Resources.HasAll(card.Input, tt.multiplier); got != (tt.multiplier <= tt.max) {
+			t.Errorf("%d yellow, x%d: HasAll %v, want %v", tt.yellow, tt.multiplier, got, tt.multiplier <= tt.max)
+		}
+	}
+}
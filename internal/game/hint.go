@@ -0,0 +1,157 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// maxHintTurns is how far ahead hint explanations look for a claimable golem
+const maxHintTurns = 3
+
+// Hint is a suggested action with a short reason
+type Hint struct {
+	Action      Action  `json:"action"`
+	Description string  `json:"description"`
+	Explanation string  `json:"explanation"`
+	Score       float64 `json:"score"`
+}
+
+// ViewFor returns a copy of the game holding only what playerID can see: the other
+// players' hands and the deck order are removed. The copy is set to playerID's turn.
+// It reads the whole game, so callers sharing the game with another goroutine must
+// hold its lock; the copy can then be searched without one.
+func (gs *GameState) ViewFor(playerID int) *GameState {
+	view := gs.Clone()
+	view.Market.ActionDeck = nil
+	view.Market.PointDeck = nil
+	for _, player := range view.Players {
+		if player.ID != playerID {
+			player.Hand = nil
+		}
+	}
+	n := len(view.Players)
+	current := view.CurrentTurn % n
+	view.CurrentTurn += (playerID - 1 - current + n) % n
+	return view
+}
+
+// Hints suggests up to n actions for playerID's turn, best first, as scored by bot
+// on the player's own view of the game
+func (gs *GameState) Hints(playerID int, bot *LookaheadBot, n int) []Hint {
+	if playerID < 1 || playerID > len(gs.Players) {
+		return nil
+	}
+	view := gs.ViewFor(playerID)
+	scored := make([]ScoredAction, 0)
+	for _, s := range bot.ScoreActions(view) {
+		if !math.IsInf(s.Score, -1) {
+			scored = append(scored, s)
+		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+	if len(scored) > n {
+		scored = scored[:n]
+	}
+
+	hints := make([]Hint, len(scored))
+	for i, s := range scored {
+		hints[i] = Hint{
+			Action:      s.Action,
+			Description: view.DescribeAction(s.Action),
+			Explanation: view.explain(s.Action),
+			Score:       s.Score,
+		}
+	}
+	return hints
+}
+
+// explain says in a few words why action helps the current player
+func (gs *GameState) explain(action Action) string {
+	player := gs.GetCurrentPlayer()
+	if action.Type == ClaimPointCard {
+		golem := gs.Market.PointCards[action.CardIndex]
+		reason := fmt.Sprintf("claims %s for %d points", golem.Name, golem.Points)
+		if coin := gs.Market.CoinOn(action.CardIndex); coin != nil {
+			reason += fmt.Sprintf(" plus a %d-point coin", coin.Points)
+		}
+		return reason
+	}
+
+	next := gs.Clone()
+	if err := next.executeAction(next.GetCurrentPlayer(), action); err != nil {
+		return ""
+	}
+	for turns := 1; turns <= maxHintTurns; turns++ {
+		if golem := next.claimableWithin(turns - 1); golem != nil {
+			if turns == 1 {
+				return fmt.Sprintf("lets you claim %s (%d points) next turn", golem.Name, golem.Points)
+			}
+			return fmt.Sprintf("gets you to %s (%d points) in %d turns", golem.Name, golem.Points, turns)
+		}
+	}
+
+	switch action.Type {
+	case AcquireCard:
+		card := gs.Market.ActionCards[action.CardIndex]
+		return fmt.Sprintf("adds %s to your engine", card.Name)
+	case Rest:
+		return fmt.Sprintf("returns %s to your hand", plural(len(player.PlayedCards), "played card"))
+	}
+	if golem, before := nearestGolem(gs.Market, player.Resources); golem != nil {
+		if after := UpgradeSteps(next.GetCurrentPlayer().Resources, golem.Requirement); after < before {
+			return fmt.Sprintf("brings you %s closer to %s", plural(before-after, "upgrade step"), golem.Name)
+		}
+	}
+	return "builds up your crystals"
+}
+
+// claimableWithin returns the most valuable golem the current player can claim
+// after at most extra more actions of their own, or nil
+func (gs *GameState) claimableWithin(extra int) *Card {
+	player := gs.GetCurrentPlayer()
+	var best *Card
+	for _, golem := range gs.Market.PointCards {
+		if golem.CanClaim(player) && (best == nil || golem.Points > best.Points) {
+			best = golem
+		}
+	}
+	if best != nil || extra == 0 {
+		return best
+	}
+	for _, action := range gs.LegalActions() {
+		if action.Type == ClaimPointCard {
+			continue
+		}
+		next := gs.Clone()
+		if err := next.executeAction(next.GetCurrentPlayer(), action); err != nil {
+			continue
+		}
+		if golem := next.claimableWithin(extra - 1); golem != nil && (best == nil || golem.Points > best.Points) {
+			best = golem
+		}
+	}
+	return best
+}
+
+// nearestGolem returns the market golem fewest upgrade steps away and its distance
+func nearestGolem(market *Market, resources *Resources) (*Card, int) {
+	var nearest *Card
+	nearestSteps := 0
+	for _, golem := range market.PointCards {
+		steps := UpgradeSteps(resources, golem.Requirement)
+		if nearest == nil || steps < nearestSteps {
+			nearest, nearestSteps = golem, steps
+		}
+	}
+	return nearest, nearestSteps
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
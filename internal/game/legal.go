@@ -0,0 +1,105 @@
+package game
+
+import "golem_century/internal/solver"
+
+// LegalActions enumerates the turn actions the current player can take right now:
+// playing a card from hand, acquiring or claiming from the market, and resting.
+// Deposits and crystal collection are left out; acquiring beyond the first market
+// slot is listed when the player can pay its cost or the deposits already cover it.
+// Trades are listed once per multiplier the player can pay. Upgrades are listed once
+// per distinct result, so an input crystal never reappears unchanged in the output.
+func (gs *GameState) LegalActions() []Action {
+	player := gs.GetCurrentPlayer()
+	actions := make([]Action, 0)
+
+	for i, card := range player.Hand {
+		if card.Type != ActionCard {
+			continue
+		}
+		if card.ActionType == Upgrade {
+			for _, pair := range UpgradeOptions(player.Resources, card.TurnUpgrade) {
+				actions = append(actions, Action{
+					Type:            PlayCard,
+					CardIndex:       i,
+					Multiplier:      1,
+					InputResources:  pair[0],
+					OutputResources: pair[1],
+				})
+			}
+			continue
+		}
+		if card.ActionType == Trade {
+			for m := 1; m <= MaxTradeMultiplier(player.Resources, card); m++ {
+				actions = append(actions, Action{Type: PlayCard, CardIndex: i, Multiplier: m})
+			}
+			continue
+		}
+		action := Action{Type: PlayCard, CardIndex: i, Multiplier: 1}
+		if card.CanPlay(player, action) {
+			actions = append(actions, action)
+		}
+	}
+
+	for i := range gs.Market.ActionCards {
+		cost := gs.Market.GetActionCardCost(i)
+		if i == 0 || gs.depositsCover(i) || player.Resources.HasAll(cost, 1) {
+			actions = append(actions, Action{Type: AcquireCard, CardIndex: i})
+		}
+	}
+
+	for i, card := range gs.Market.PointCards {
+		if card.CanClaim(player) {
+			actions = append(actions, Action{Type: ClaimPointCard, CardIndex: i})
+		}
+	}
+
+	return append(actions, Action{Type: Rest})
+}
+
+// depositsCover reports whether every market card before index carries a deposit,
+// which makes acquiring the card at index free
+func (gs *GameState) depositsCover(index int) bool {
+	for i := 0; i < index && i < len(gs.Market.ActionCards); i++ {
+		if len(gs.Market.ActionCards[i].Deposits[i+1]) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// UpgradeOptions lists the (input, output) pairs an upgrade card with maxUpgrade
+// levels can apply to resources, one per distinct result. Inputs and outputs share
+// no colour, since an unchanged crystal gives the same result as the smaller upgrade without it.
+func UpgradeOptions(resources *Resources, maxUpgrade int) [][2]*Resources {
+	upgrades := solver.Options(resources.Crystals(), maxUpgrade)
+	options := make([][2]*Resources, len(upgrades))
+	for i, u := range upgrades {
+		options[i] = [2]*Resources{ResourcesFromCrystals(u.In), ResourcesFromCrystals(u.Out)}
+	}
+	return options
+}
+
+// PlanUpgrade returns the best play of the upgrade card at hand index cardIndex:
+// toward requirement when given, otherwise for the most crystal value.
+// It reports false when the player has nothing to upgrade.
+func PlanUpgrade(player *Player, card *Card, cardIndex int, requirement *Resources) (Action, bool) {
+	if card.Type != ActionCard || card.ActionType != Upgrade {
+		return Action{}, false
+	}
+	var target solver.Target
+	if requirement != nil {
+		need := requirement.Crystals()
+		target.Requirement = &need
+	}
+	upgrade, ok := solver.Best(player.Resources.Crystals(), card.TurnUpgrade, target)
+	if !ok {
+		return Action{}, false
+	}
+	return Action{
+		Type:            PlayCard,
+		CardIndex:       cardIndex,
+		Multiplier:      1,
+		InputResources:  ResourcesFromCrystals(upgrade.In),
+		OutputResources: ResourcesFromCrystals(upgrade.Out),
+	}, true
+}
@@ -0,0 +1,311 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+
+	"golem_century/internal/solver"
+)
+
+// Weights tune the lookahead bot's evaluation function
+type Weights struct {
+	Points     float64 `json:"points"`     // Per point already scored (golems and coins)
+	Golems     float64 `json:"golems"`     // Per point of the best visible golem, scaled down by its distance
+	StepDecay  float64 `json:"stepDecay"`  // Factor applied to a golem's value per upgrade step still missing
+	Coins      float64 `json:"coins"`      // Per point of the coin on a golem's slot, scaled down like the golem
+	Crystals   float64 `json:"crystals"`   // Per crystal level held (yellow 1 ... pink 4)
+	Overflow   float64 `json:"overflow"`   // Penalty per crystal above MaxCrystals
+	Engine     float64 `json:"engine"`     // Per unit of engine strength in hand and played cards
+	TradeCards float64 `json:"tradeCards"` // Per trade card owned, on top of its engine strength
+	Discount   float64 `json:"discount"`   // Factor applied to positions one more ply away
+}
+
+// DefaultWeights returns the hand-tuned weights the bot uses without a weights file
+func DefaultWeights() Weights {
+	return Weights{
+		Points:    1.0,
+		Golems:    0.5,
+		StepDecay: 0.75,
+		Coins:     0.5,
+		Crystals:  0.15,
+		Overflow:  1.0,
+		Engine:    0.3,
+		Discount:  0.95,
+	}
+}
+
+// LoadWeights reads weights from a JSON file. Fields missing from the file keep
+// their default value.
+func LoadWeights(path string) (Weights, error) {
+	weights := DefaultWeights()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return weights, err
+	}
+	if err := json.Unmarshal(data, &weights); err != nil {
+		return weights, fmt.Errorf("parse weights %s: %w", path, err)
+	}
+	return weights, nil
+}
+
+// SaveWeights writes weights as indented JSON
+func SaveWeights(path string, weights Weights) error {
+	data, err := json.MarshalIndent(weights, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Lookahead depth limits
+const (
+	MinLookaheadDepth = 1
+	MaxLookaheadDepth = 3
+)
+
+// LookaheadBot searches sequences of its own next actions, ignoring what opponents
+// do in between, and picks the action leading to the best-scoring position.
+// The search never sees the order of the decks or the opponents' hands.
+type LookaheadBot struct {
+	Weights  Weights
+	Depth    int     // Plies of own actions to search (1-3)
+	Noise    float64 // Standard deviation of random noise added to each action's score
+	Mistakes float64 // Chance of playing a random legal action instead of searching
+	Paths    int     // Turns of path search measuring golem distances one action ahead; 0 counts upgrade steps only
+	Budget   int     // Positions searched per move at most; 0 means no limit
+
+	rng *rand.Rand // Source of noise and mistakes; nil plays deterministically
+}
+
+const (
+	// defaultPathTurns is the path search horizon of new lookahead bots. Only the
+	// positions one action away get a path search.
+	defaultPathTurns = 3
+	// defaultSearchBudget bounds the positions new lookahead bots search per move,
+	// which keeps a move under a second even with a 20-card hand
+	defaultSearchBudget = 20000
+)
+
+// NewLookaheadBot creates a lookahead bot, clamping depth to the supported range
+func NewLookaheadBot(weights Weights, depth int) *LookaheadBot {
+	if depth < MinLookaheadDepth {
+		depth = MinLookaheadDepth
+	}
+	if depth > MaxLookaheadDepth {
+		depth = MaxLookaheadDepth
+	}
+	return &LookaheadBot{Weights: weights, Depth: depth, Paths: defaultPathTurns, Budget: defaultSearchBudget}
+}
+
+// ChooseAction selects the legal action with the best lookahead score
+func (b *LookaheadBot) ChooseAction(player *Player, market *Market, gameState *GameState) Action {
+	if b.rng != nil && b.Mistakes > 0 && b.rng.Float64() < b.Mistakes {
+		actions := gameState.LegalActions()
+		return actions[b.rng.Intn(len(actions))]
+	}
+
+	scored := b.ScoreActions(gameState)
+	if len(scored) == 0 {
+		return Action{Type: Rest}
+	}
+	if b.rng != nil && b.Noise > 0 {
+		for i := range scored {
+			scored[i].Score += b.rng.NormFloat64() * b.Noise
+		}
+	}
+	best := scored[0]
+	for _, s := range scored[1:] {
+		if s.Score > best.Score {
+			best = s
+		}
+	}
+	return best.Action
+}
+
+// ScoredAction is a legal action with its lookahead score
+type ScoredAction struct {
+	Action Action
+	Score  float64
+}
+
+// ScoreActions scores every legal action of the current player, in LegalActions order.
+// The search deepens one ply at a time while the budget lasts; the scores are those
+// of the deepest search that finished.
+func (b *LookaheadBot) ScoreActions(gameState *GameState) []ScoredAction {
+	root := gameState.Clone()
+	// Cards revealed by refills during the search would leak the deck order
+	root.Market.ActionDeck = nil
+	root.Market.PointDeck = nil
+
+	actions := root.LegalActions()
+	budget := b.Budget
+	if budget <= 0 {
+		budget = math.MaxInt
+	}
+
+	// The positions one action away are evaluated once, and only they get the
+	// path search: it is too slow to run on every position searched
+	children := make([]*GameState, len(actions))
+	stops := make([]float64, len(actions))
+	scored := make([]ScoredAction, len(actions))
+	for i, action := range actions {
+		budget--
+		children[i] = root.Clone()
+		player := children[i].GetCurrentPlayer()
+		if err := children[i].executeAction(player, action); err != nil {
+			children[i], stops[i] = nil, math.Inf(-1)
+		} else {
+			stops[i] = b.evaluate(player, children[i].Market, b.Paths)
+		}
+		scored[i] = ScoredAction{Action: action, Score: stops[i]}
+	}
+
+	for depth := 2; depth <= b.maxDepth(len(actions)); depth++ {
+		deeper := make([]ScoredAction, len(actions))
+		for i, child := range children {
+			deeper[i] = scored[i]
+			if child == nil {
+				continue
+			}
+			score, ok := b.deepen(child, stops[i], depth-1, &budget)
+			if !ok {
+				return scored
+			}
+			deeper[i].Score = score
+		}
+		scored = deeper
+	}
+	return scored
+}
+
+// maxDepth caps the search depth so that searching width legal actions a ply
+// fits the budget. With many legal actions the bot looks less far ahead.
+func (b *LookaheadBot) maxDepth(width int) int {
+	depth, nodes := 1, width
+	for depth < b.Depth && (b.Budget <= 0 || nodes*width <= b.Budget) {
+		depth++
+		nodes *= width
+	}
+	return depth
+}
+
+// deepen returns the better of stop, the score of stopping at state, and the
+// discounted best score of up to depth more actions. Stopping early is allowed, so
+// a good position is never lost to a forced bad follow-up. It reports false when
+// the budget ran out before it finished.
+func (b *LookaheadBot) deepen(state *GameState, stop float64, depth int, budget *int) (float64, bool) {
+	score := stop
+	for _, followUp := range state.LegalActions() {
+		s, ok := b.search(state, followUp, depth, budget)
+		if !ok {
+			return score, false
+		}
+		if s *= b.Weights.Discount; s > score {
+			score = s
+		}
+	}
+	return score, true
+}
+
+// search returns the best score reachable by playing action and then up to depth-1
+// more actions. Each position searched spends one unit of budget.
+func (b *LookaheadBot) search(state *GameState, action Action, depth int, budget *int) (float64, bool) {
+	if *budget <= 0 {
+		return 0, false
+	}
+	*budget--
+	next := state.Clone()
+	player := next.GetCurrentPlayer()
+	if err := next.executeAction(player, action); err != nil {
+		return math.Inf(-1), true
+	}
+	score := b.evaluate(player, next.Market, 0)
+	if depth <= 1 {
+		return score, true
+	}
+	return b.deepen(next, score, depth-1, budget)
+}
+
+// Evaluate scores a player's position; higher is better
+func (b *LookaheadBot) Evaluate(player *Player, market *Market) float64 {
+	return b.evaluate(player, market, b.Paths)
+}
+
+// evaluate scores a position, measuring golem distances with a path search of
+// pathTurns turns, or by upgrade steps alone when pathTurns is 0
+func (b *LookaheadBot) evaluate(player *Player, market *Market, pathTurns int) float64 {
+	w := b.Weights
+	score := w.Points * float64(player.GetPoints())
+	score += w.Crystals * float64(player.Resources.GetLevels())
+	if over := player.Resources.Total() - MaxCrystals; over > 0 {
+		score -= w.Overflow * float64(over)
+	}
+	score += w.Engine * (EngineStrength(player.Hand) + EngineStrength(player.PlayedCards))
+	score += w.TradeCards * float64(countActionCards(player.Hand, Trade)+countActionCards(player.PlayedCards, Trade))
+
+	// Only the most promising golem counts: the same crystals cannot pay for all of them
+	var paths []GolemPath
+	if pathTurns > 0 {
+		paths = FindGolemPaths(player, market.PointCards, pathTurns)
+	}
+	best := 0.0
+	for i, golem := range market.PointCards {
+		value := w.Golems * float64(golem.Points)
+		if coin := market.CoinOn(i); coin != nil {
+			value += w.Coins * float64(coin.Points)
+		}
+		steps := UpgradeSteps(player.Resources, golem.Requirement)
+		// A golem the cards in hand reach in fewer turns than it has steps is closer than it looks
+		if paths != nil && paths[i].Reachable {
+			steps = min(steps, paths[i].Turns-1)
+		}
+		best = math.Max(best, value*math.Pow(w.StepDecay, float64(steps)))
+	}
+	return score + best
+}
+
+// EngineStrength sums how many crystal levels one play of each action card gains:
+// the output of a produce card, the levels of an upgrade card and the net gain of a trade
+func EngineStrength(cards []*Card) float64 {
+	strength := 0.0
+	for _, card := range cards {
+		if card.Type != ActionCard {
+			continue
+		}
+		switch card.ActionType {
+		case Produce:
+			if card.Output != nil {
+				strength += float64(card.Output.GetLevels())
+			}
+		case Upgrade:
+			strength += float64(card.TurnUpgrade)
+		case Trade:
+			if card.Input != nil && card.Output != nil {
+				strength += math.Max(0, float64(card.Output.GetLevels()-card.Input.GetLevels()))
+			}
+		}
+	}
+	return strength
+}
+
+func countActionCards(cards []*Card, actionType ActionType) int {
+	count := 0
+	for _, card := range cards {
+		if card.Type == ActionCard && card.ActionType == actionType {
+			count++
+		}
+	}
+	return count
+}
+
+// UpgradeSteps estimates how many single-level crystal upgrades turn have into a
+// superset of need (see solver.Steps)
+func UpgradeSteps(have, need *Resources) int {
+	if need == nil {
+		return 0
+	}
+	return solver.Steps(have.Crystals(), need.Crystals())
+}
@@ -0,0 +1,67 @@
+package game
+
+import "testing"
+
+// wideState gives the current player a hand of 20 cards and crystals to play them
+func wideState() *GameState {
+	gs := NewGameState(5, 3)
+	gs.quiet = true
+	player := gs.GetCurrentPlayer()
+	player.Hand = append(player.Hand, CreateDefaultActionCards()[:18]...)
+	player.Resources = &Resources{Yellow: 3, Green: 2, Blue: 1, Pink: 1}
+	return gs
+}
+
+func TestMaxDepth(t *testing.T) {
+	tests := []struct {
+		depth, budget, width, want int
+	}{
+		{3, 0, 45, 3},     // No budget, no cap
+		{3, 1000, 10, 3},  // 10 x 10 x 10 fits
+		{3, 1000, 11, 2},  // 11 x 11 x 11 does not
+		{3, 1000, 45, 1},  // 45 x 45 does not either
+		{2, 20000, 5, 2},  // Never deeper than asked
+		{3, 20000, 45, 2}, // A 20-card hand with the default budget
+		{1, 1, 100, 1},    // One ply is always searched
+	}
+	for _, tt := range tests {
+		bot := &LookaheadBot{Depth: tt.depth, Budget: tt.budget}
+		if got := bot.maxDepth(tt.width); got != tt.want {
+			t.Errorf("depth %d, budget %d, %d actions: got %d, want %d", tt.depth, tt.budget, tt.width, got, tt.want)
+		}
+	}
+}
+
+// When the budget runs out, the deepest finished search decides
+func TestScoreActionsWithinBudget(t *testing.T) {
+	gs := wideState()
+	width := len(gs.LegalActions())
+
+	shallow := &LookaheadBot{Weights: DefaultWeights(), Depth: 1}
+	want := shallow.ScoreActions(gs)
+	for _, budget := range []int{1, width, width + 10} {
+		bot := &LookaheadBot{Weights: DefaultWeights(), Depth: 3, Budget: budget}
+		got := bot.ScoreActions(gs)
+		if len(got) != len(want) {
+			t.Fatalf("budget %d: scored %d actions, want %d", budget, len(got), len(want))
+		}
+		for i := range got {
+			if got[i].Score != want[i].Score {
+				t.Errorf("budget %d: action %d scored %v, want the one-ply %v", budget, i, got[i].Score, want[i].Score)
+			}
+		}
+	}
+}
+
+// A budget the full search fits in changes nothing
+func TestScoreActionsDeepens(t *testing.T) {
+	gs := wideState()
+	width := len(gs.LegalActions())
+	unlimited := (&LookaheadBot{Weights: DefaultWeights(), Depth: 2}).ScoreActions(gs)
+	budgeted := (&LookaheadBot{Weights: DefaultWeights(), Depth: 2, Budget: width * width * 2}).ScoreActions(gs)
+	for i := range unlimited {
+		if unlimited[i].Score != budgeted[i].Score {
+			t.Errorf("action %d: scored %v with a budget, %v without", i, budgeted[i].Score, unlimited[i].Score)
+		}
+	}
+}
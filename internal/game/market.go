@@ -11,34 +11,26 @@ type Market struct {
 	PointCards       []*Card // Available point cards (face up)
 	ActionDeck       []*Card // Deck of action cards
 	PointDeck        []*Card // Deck of point cards
-	Coins            []*Card // Available bonus coins (face up)
+	Coins            []*Card // Coin piles above the first golem slots, in slot order; empty piles are removed
 	MaxActionVisible int     // Maximum visible action cards in market
 	MaxPointVisible  int     // Maximum visible point cards in market
 }
 
-// NewMarket creates a new market with shuffled decks
-func NewMarket(actionCards, pointCards []*Card, coins []*Card, maxActionVisible int, maxPointVisible int, rng *rand.Rand) *Market {
-	// Shuffle decks
-	shuffledActions := make([]*Card, len(actionCards))
-	copy(shuffledActions, actionCards)
-	shuffledPoints := make([]*Card, len(pointCards))
-	copy(shuffledPoints, pointCards)
-
-	// Shuffle using Fisher-Yates
-	for i := len(shuffledActions) - 1; i > 0; i-- {
-		j := rng.Intn(i + 1)
-		shuffledActions[i], shuffledActions[j] = shuffledActions[j], shuffledActions[i]
-	}
-	for i := len(shuffledPoints) - 1; i > 0; i-- {
-		j := rng.Intn(i + 1)
-		shuffledPoints[i], shuffledPoints[j] = shuffledPoints[j], shuffledPoints[i]
-	}
+// NewMarket creates a new market with shuffled decks and coinsPerPile coins on each coin pile
+func NewMarket(actionCards, pointCards []*Card, coins []*Card, maxActionVisible int, maxPointVisible int, coinsPerPile int, rng *rand.Rand) *Market {
+	shuffledActions := shuffleCards(actionCards, rng)
+	shuffledPoints := shuffleCards(pointCards, rng)
+	return NewOrderedMarket(shuffledActions, shuffledPoints, coins, maxActionVisible, maxPointVisible, coinsPerPile)
+}
 
+// NewOrderedMarket creates a market that deals both decks in the order given, first
+// card first, without shuffling. It is the setup hook for scripted games.
+func NewOrderedMarket(actionDeck, pointDeck []*Card, coins []*Card, maxActionVisible int, maxPointVisible int, coinsPerPile int) *Market {
 	market := &Market{
 		ActionCards:      make([]*Card, 0),
 		PointCards:       make([]*Card, 0),
-		ActionDeck:       shuffledActions,
-		PointDeck:        shuffledPoints,
+		ActionDeck:       actionDeck,
+		PointDeck:        pointDeck,
 		Coins:            coins,
 		MaxActionVisible: maxActionVisible,
 		MaxPointVisible:  maxPointVisible,
@@ -47,7 +39,7 @@ func NewMarket(actionCards, pointCards []*Card, coins []*Card, maxActionVisible
 	// Draw initial cards
 	market.RefillActionCards()
 	market.RefillPointCards()
-	market.RefillCoins()
+	market.RefillCoins(coinsPerPile)
 
 	return market
 }
@@ -68,10 +60,56 @@ func (m *Market) RefillPointCards() {
 	}
 }
 
-func (m *Market) RefillCoins() {
+// RefillCoins sets every coin pile to amount coins
+func (m *Market) RefillCoins(amount int) {
 	for i := range m.Coins {
-		m.Coins[i].Amount = 10
+		m.Coins[i].Amount = amount
+	}
+}
+
+// SetCoins sets the coins on each pile in slot order and removes the piles left empty
+func (m *Market) SetCoins(amounts []int) {
+	piles := make([]*Card, 0, len(m.Coins))
+	for i, pile := range m.Coins {
+		if i < len(amounts) {
+			pile.Amount = amounts[i]
+		}
+		if pile.Amount > 0 {
+			piles = append(piles, pile)
+		}
+	}
+	m.Coins = piles
+}
+
+// CoinSlots is how many golem slots start with a coin pile
+const CoinSlots = 2
+
+// CoinOn returns the coin pile above the golem at slot, or nil when the slot has no coins
+func (m *Market) CoinOn(slot int) *Card {
+	if slot < 0 || slot >= len(m.Coins) || m.Coins[slot].Amount <= 0 {
+		return nil
+	}
+	return m.Coins[slot]
+}
+
+// TakeCoin takes one coin from the pile above the golem at slot and returns it as a
+// card of its own, or nil when the slot has no coins. When a pile runs out the piles
+// behind it move up a slot, so silver moves to the first golem once copper is gone.
+// Each coin gets its own ID: the pile's ID times 100 plus its place in the pile.
+func (m *Market) TakeCoin(slot int) *Card {
+	pile := m.CoinOn(slot)
+	if pile == nil {
+		return nil
+	}
+	coin := *pile
+	coin.ID = pile.ID*100 + pile.Amount
+	coin.Amount = 1
+	coin.Deposits = make(map[int][]CrystalType)
+	pile.Amount--
+	if pile.Amount == 0 {
+		m.Coins = append(m.Coins[:slot], m.Coins[slot+1:]...)
 	}
+	return &coin
 }
 
 // GetActionCardCost returns the cost to acquire an action card at a given position
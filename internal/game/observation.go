@@ -0,0 +1,123 @@
+package game
+
+// CardView is the public description of a card
+type CardView struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	ActionType  string     `json:"actionType,omitempty"` // produce, upgrade or trade (action cards)
+	Input       *Resources `json:"input,omitempty"`
+	Output      *Resources `json:"output,omitempty"`
+	TurnUpgrade int        `json:"turnUpgrade,omitempty"`
+	Requirement *Resources `json:"requirement,omitempty"` // Point cards
+	Points      int        `json:"points,omitempty"`
+	Cost        *Resources `json:"cost,omitempty"` // Market action cards
+}
+
+// OpponentView is what a player can see of another player
+type OpponentView struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Resources   Resources  `json:"resources"`
+	HandSize    int        `json:"handSize"`
+	PlayedCards []CardView `json:"playedCards"`
+	PointCards  []CardView `json:"pointCards"`
+	Points      int        `json:"points"`
+}
+
+// Observation is one player's view of the game. Opponents' hands and the
+// order of the decks are hidden.
+type Observation struct {
+	PlayerID         int            `json:"playerID"`
+	Turn             int            `json:"turn"`
+	Round            int            `json:"round"`
+	LastRound        bool           `json:"lastRound"`
+	Resources        Resources      `json:"resources"`
+	Hand             []CardView     `json:"hand"`
+	PlayedCards      []CardView     `json:"playedCards"`
+	PointCards       []CardView     `json:"pointCards"`
+	Points           int            `json:"points"`
+	PendingDiscard   int            `json:"pendingDiscard"`
+	Opponents        []OpponentView `json:"opponents"`
+	MarketCards      []CardView     `json:"marketCards"`
+	MarketPointCards []CardView     `json:"marketPointCards"`
+	CoinsLeft        []int          `json:"coinsLeft"`  // Coins left above each of the first CoinSlots golems
+	CoinPoints       []int          `json:"coinPoints"` // Points of the coin above each of those golems (0 for none)
+	ActionDeckSize   int            `json:"actionDeckSize"`
+	PointDeckSize    int            `json:"pointDeckSize"`
+}
+
+var actionTypeViewNames = map[ActionType]string{
+	Produce: "produce",
+	Upgrade: "upgrade",
+	Trade:   "trade",
+}
+
+// Observe returns playerID's view of the game
+func (gs *GameState) Observe(playerID int) Observation {
+	obs := Observation{
+		PlayerID:         playerID,
+		Turn:             gs.CurrentTurn + 1,
+		Round:            gs.Round,
+		LastRound:        gs.LastRound,
+		Opponents:        make([]OpponentView, 0, len(gs.Players)-1),
+		MarketCards:      make([]CardView, len(gs.Market.ActionCards)),
+		MarketPointCards: viewCards(gs.Market.PointCards),
+		CoinsLeft:        make([]int, CoinSlots),
+		CoinPoints:       make([]int, CoinSlots),
+		ActionDeckSize:   len(gs.Market.ActionDeck),
+		PointDeckSize:    len(gs.Market.PointDeck),
+	}
+	for _, p := range gs.Players {
+		if p.ID == playerID {
+			obs.Resources = *p.Resources
+			obs.Hand = viewCards(p.Hand)
+			obs.PlayedCards = viewCards(p.PlayedCards)
+			obs.PointCards = viewCards(p.PointCards)
+			obs.Points = p.GetPoints()
+			obs.PendingDiscard = p.PendingDiscard
+			continue
+		}
+		obs.Opponents = append(obs.Opponents, OpponentView{
+			ID:          p.ID,
+			Name:        p.Name,
+			Resources:   *p.Resources,
+			HandSize:    len(p.Hand),
+			PlayedCards: viewCards(p.PlayedCards),
+			PointCards:  viewCards(p.PointCards),
+			Points:      p.GetPoints(),
+		})
+	}
+	for i, card := range gs.Market.ActionCards {
+		obs.MarketCards[i] = viewCard(card)
+		obs.MarketCards[i].Cost = gs.Market.GetActionCardCost(i)
+	}
+	for i := 0; i < CoinSlots; i++ {
+		if coin := gs.Market.CoinOn(i); coin != nil {
+			obs.CoinsLeft[i] = coin.Amount
+			obs.CoinPoints[i] = coin.Points
+		}
+	}
+	return obs
+}
+
+func viewCards(cards []*Card) []CardView {
+	views := make([]CardView, len(cards))
+	for i, card := range cards {
+		views[i] = viewCard(card)
+	}
+	return views
+}
+
+func viewCard(card *Card) CardView {
+	view := CardView{ID: card.ID, Name: card.Name, Points: card.Points}
+	switch card.Type {
+	case ActionCard:
+		view.ActionType = actionTypeViewNames[card.ActionType]
+		view.Input = card.Input
+		view.Output = card.Output
+		view.TurnUpgrade = card.TurnUpgrade
+	case PointCard:
+		view.Requirement = card.Requirement
+	}
+	return view
+}
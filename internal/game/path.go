@@ -0,0 +1,210 @@
+package game
+
+import "golem_century/internal/solver"
+
+// MaxPathTurns is how many turns ahead GolemPaths looks by default
+const MaxPathTurns = 8
+
+// maxPathStates bounds the positions a path search visits, so a large hand cannot stall it
+const maxPathStates = 100000
+
+// GolemPath is the quickest way a player can claim a market golem with the cards
+// they already own: playing cards from hand, resting, then claiming
+type GolemPath struct {
+	GolemIndex int      `json:"golemIndex"`
+	Name       string   `json:"name"`
+	Points     int      `json:"points"`
+	Reachable  bool     `json:"reachable"`
+	Turns      int      `json:"turns"`   // Own turns needed, the claim included; 0 when not reachable
+	Actions    []Action `json:"actions"` // One action per turn, ending with the claim
+}
+
+// GolemPaths finds the shortest path to every golem in the market for playerID
+func (gs *GameState) GolemPaths(playerID int) []GolemPath {
+	if playerID < 1 || playerID > len(gs.Players) {
+		return nil
+	}
+	return FindGolemPaths(gs.Players[playerID-1], gs.Market.PointCards, MaxPathTurns)
+}
+
+// pathNode is a position in the path search. hand and played index into the
+// player's owned cards, in the order the game would keep them.
+type pathNode struct {
+	resources solver.Crystals
+	hand      []int
+	played    []int
+	parent    *pathNode
+	action    Action
+	turns     int
+}
+
+// pathKey identifies positions that play out the same: the crystals held and which
+// owned cards are in hand. A player owns fewer than 64 action cards.
+type pathKey struct {
+	resources solver.Crystals
+	hand      uint64
+}
+
+func (n *pathNode) key() pathKey {
+	k := pathKey{resources: n.resources}
+	for _, c := range n.hand {
+		k.hand |= 1 << c
+	}
+	return k
+}
+
+// actions returns the actions leading from the search root to n
+func (n *pathNode) actions() []Action {
+	actions := make([]Action, n.turns)
+	for ; n.parent != nil; n = n.parent {
+		actions[n.turns-1] = n.action
+	}
+	return actions
+}
+
+// FindGolemPaths searches breadth-first through the player's own plays and rests,
+// ignoring opponents and market changes, for the fewest turns to claim each golem.
+// Golems not claimable within maxTurns are returned unreachable. Crystals above
+// MaxCrystals are discarded cheapest first.
+func FindGolemPaths(player *Player, golems []*Card, maxTurns int) []GolemPath {
+	paths := make([]GolemPath, len(golems))
+	needs := make([]solver.Crystals, len(golems))
+	left := 0
+	for i, golem := range golems {
+		paths[i] = GolemPath{GolemIndex: i, Name: golem.Name, Points: golem.Points}
+		if golem.Requirement != nil {
+			needs[i] = golem.Requirement.Crystals()
+			left++
+		}
+	}
+
+	cards := append(append([]*Card{}, player.Hand...), player.PlayedCards...)
+	if len(cards) > 64 {
+		cards = cards[:64]
+	}
+	root := &pathNode{resources: player.Resources.Crystals()}
+	for i := range cards {
+		if i < len(player.Hand) {
+			root.hand = append(root.hand, i)
+		} else {
+			root.played = append(root.played, i)
+		}
+	}
+
+	seen := map[pathKey]bool{root.key(): true}
+	level := []*pathNode{root}
+	for turns := 1; turns <= maxTurns && left > 0 && len(level) > 0; turns++ {
+		var next []*pathNode
+		for _, node := range level {
+			for i, golem := range golems {
+				if paths[i].Reachable || golem.Requirement == nil || !covers(node.resources, needs[i]) {
+					continue
+				}
+				paths[i].Reachable = true
+				paths[i].Turns = turns
+				paths[i].Actions = append(node.actions(), Action{Type: ClaimPointCard, CardIndex: i})
+				left--
+			}
+			if turns == maxTurns || left == 0 {
+				continue
+			}
+			for _, child := range node.children(cards) {
+				if k := child.key(); !seen[k] && len(seen) < maxPathStates {
+					seen[k] = true
+					next = append(next, child)
+				}
+			}
+		}
+		level = next
+	}
+	return paths
+}
+
+// children returns the positions one turn after n
+func (n *pathNode) children(cards []*Card) []*pathNode {
+	children := make([]*pathNode, 0)
+	play := func(i int, resources solver.Crystals, action Action) {
+		child := &pathNode{
+			resources: discardOverflow(resources),
+			hand:      append(append(make([]int, 0, len(n.hand)-1), n.hand[:i]...), n.hand[i+1:]...),
+			played:    append(append(make([]int, 0, len(n.played)+1), n.played...), n.hand[i]),
+			parent:    n,
+			action:    action,
+			turns:     n.turns + 1,
+		}
+		children = append(children, child)
+	}
+
+	for i, c := range n.hand {
+		card := cards[c]
+		if card.Type != ActionCard {
+			continue
+		}
+		switch card.ActionType {
+		case Produce:
+			if card.Output != nil {
+				play(i, addCrystals(n.resources, card.Output.Crystals(), 1), Action{Type: PlayCard, CardIndex: i, Multiplier: 1})
+			}
+		case Trade:
+			if card.Input == nil || card.Output == nil {
+				continue
+			}
+			have := ResourcesFromCrystals(n.resources)
+			in, out := card.Input.Crystals(), card.Output.Crystals()
+			for m := 1; m <= MaxTradeMultiplier(have, card); m++ {
+				play(i, addCrystals(addCrystals(n.resources, in, -m), out, m), Action{Type: PlayCard, CardIndex: i, Multiplier: m})
+			}
+		case Upgrade:
+			for _, u := range solver.Options(n.resources, card.TurnUpgrade) {
+				play(i, u.Apply(n.resources), Action{
+					Type:            PlayCard,
+					CardIndex:       i,
+					Multiplier:      1,
+					InputResources:  ResourcesFromCrystals(u.In),
+					OutputResources: ResourcesFromCrystals(u.Out),
+				})
+			}
+		}
+	}
+
+	if len(n.played) > 0 {
+		children = append(children, &pathNode{
+			resources: n.resources,
+			hand:      append(append(make([]int, 0, len(n.hand)+len(n.played)), n.hand...), n.played...),
+			parent:    n,
+			action:    Action{Type: Rest},
+			turns:     n.turns + 1,
+		})
+	}
+	return children
+}
+
+func covers(have, need solver.Crystals) bool {
+	for c := range need {
+		if have[c] < need[c] {
+			return false
+		}
+	}
+	return true
+}
+
+func addCrystals(have, other solver.Crystals, multiplier int) solver.Crystals {
+	for c := range have {
+		have[c] += other[c] * multiplier
+	}
+	return have
+}
+
+// discardOverflow drops crystals above MaxCrystals, yellow first
+func discardOverflow(have solver.Crystals) solver.Crystals {
+	over := have.Total() - MaxCrystals
+	for c := range have {
+		if over <= 0 {
+			break
+		}
+		n := min(over, have[c])
+		have[c] -= n
+		over -= n
+	}
+	return have
+}
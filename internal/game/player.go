@@ -15,6 +15,7 @@ type Player struct {
 	IsAI          bool
 	HasRested     bool // Whether player has rested this round
 	PendingDiscard int // Number of crystals that must be discarded (0 = no discard needed)
+	Stats         PlayerStats // What the player has done this game
 }
 
 // NewPlayer creates a new player
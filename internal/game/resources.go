@@ -3,6 +3,8 @@ package game
 import (
 	"fmt"
 	"strings"
+
+	"golem_century/internal/solver"
 )
 
 // CrystalType represents the type of crystal
@@ -28,10 +30,10 @@ const MaxCrystals = 10
 
 // Resources represents a collection of crystals
 type Resources struct {
-	Yellow int
-	Green  int
-	Blue   int
-	Pink   int
+	Yellow int `json:"yellow"`
+	Green  int `json:"green"`
+	Blue   int `json:"blue"`
+	Pink   int `json:"pink"`
 }
 
 // NewResources creates a new empty Resources struct
@@ -39,6 +41,16 @@ func NewResources() *Resources {
 	return &Resources{}
 }
 
+// Crystals converts the resources to the solver's crystal counts
+func (r *Resources) Crystals() solver.Crystals {
+	return solver.Crystals{r.Yellow, r.Green, r.Blue, r.Pink}
+}
+
+// ResourcesFromCrystals converts solver crystal counts to resources
+func ResourcesFromCrystals(c solver.Crystals) *Resources {
+	return &Resources{Yellow: c[solver.Yellow], Green: c[solver.Green], Blue: c[solver.Blue], Pink: c[solver.Pink]}
+}
+
 // Get returns the count of a specific crystal type
 func (r *Resources) Get(crystal CrystalType) int {
 	switch crystal {
@@ -106,15 +118,21 @@ func (r *Resources) Has(crystal CrystalType, count int) bool {
 	return r.Get(crystal) >= count
 }
 
-// HasAll checks if the resources have all the required crystals
+// HasAll checks if the resources have all the required crystals, multiplier times over
 func (r *Resources) HasAll(required *Resources, multiplier int) bool {
 	if multiplier <= 0 {
 		multiplier = 1
 	}
-	return r.Yellow >= required.Yellow &&
-		r.Green >= required.Green &&
-		r.Blue >= required.Blue &&
-		r.Pink >= required.Pink
+	return pays(r.Yellow, required.Yellow, multiplier) &&
+		pays(r.Green, required.Green, multiplier) &&
+		pays(r.Blue, required.Blue, multiplier) &&
+		pays(r.Pink, required.Pink, multiplier)
+}
+
+// pays reports whether have crystals pay for need crystals multiplier times.
+// It divides rather than multiplies, so a huge multiplier cannot overflow.
+func pays(have, need, multiplier int) bool {
+	return need <= 0 || have/need >= multiplier
 }
 
 // SubtractAll subtracts all required resources (returns false if insufficient)
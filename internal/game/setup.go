@@ -0,0 +1,242 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// defaultGolemsToEnd is how many golems a player claims to trigger the last round
+const defaultGolemsToEnd = 5
+
+// GameSetup describes a game to set up explicitly. Anything left zero gets the
+// standard setup, so GameSetup{NumPlayers: 3, Seed: 1} matches NewGameState(3, 1).
+// Decks that are listed are dealt in the order given; the seed only shuffles the others.
+type GameSetup struct {
+	NumPlayers  int         `json:"numPlayers,omitempty"` // Seats when Seats is empty
+	Seed        int64       `json:"seed"`
+	Seats       []SeatSetup `json:"seats,omitempty"`       // One per seat, in turn order
+	ActionCards []string    `json:"actionCards,omitempty"` // Market row then deck, first card first
+	PointCards  []string    `json:"pointCards,omitempty"`  // Golem row then deck, first card first
+	Coins       []int       `json:"coins,omitempty"`       // Coins per pile in slot order (copper, silver); default 2 per player
+	Rules       Rules       `json:"rules"`
+}
+
+// SeatSetup sets up one seat
+type SeatSetup struct {
+	Name      string     `json:"name,omitempty"`      // Default "Player N"
+	Resources *Resources `json:"resources,omitempty"` // Default depends on the seat
+	Hand      []string   `json:"hand,omitempty"`      // Default is the two starting cards
+}
+
+// Rules are the variable rules of a game
+type Rules struct {
+	MarketActionCards int `json:"marketActionCards,omitempty"` // Visible action cards; default depends on the player count
+	MarketPointCards  int `json:"marketPointCards,omitempty"`  // Visible golems; default 5
+	GolemsToEnd       int `json:"golemsToEnd,omitempty"`       // Golems that trigger the last round; default 5
+}
+
+// LoadSetup reads a scenario file: a GameSetup as JSON
+func LoadSetup(path string) (GameSetup, error) {
+	var setup GameSetup
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return setup, err
+	}
+	if err := json.Unmarshal(data, &setup); err != nil {
+		return setup, fmt.Errorf("parse scenario %s: %w", path, err)
+	}
+	return setup, nil
+}
+
+// seats returns the number of seats the setup asks for
+func (s GameSetup) seats() int {
+	if len(s.Seats) > 0 {
+		return len(s.Seats)
+	}
+	return s.NumPlayers
+}
+
+// Validate checks the seat count, the card names and the coin piles. A single
+// seat is allowed, for puzzles.
+func (s GameSetup) Validate() error {
+	if n := s.seats(); n < 1 || n > MaxPlayers {
+		return fmt.Errorf("a game has 1 to %d seats, not %d", MaxPlayers, n)
+	}
+	if s.NumPlayers != 0 && len(s.Seats) != 0 && s.NumPlayers != len(s.Seats) {
+		return fmt.Errorf("numPlayers is %d but %d seats are set up", s.NumPlayers, len(s.Seats))
+	}
+	for i, seat := range s.Seats {
+		if err := checkCardNames(seat.Hand, isActionCardName); err != nil {
+			return fmt.Errorf("seat %d hand: %w", i+1, err)
+		}
+		if r := seat.Resources; r != nil && (r.Yellow < 0 || r.Green < 0 || r.Blue < 0 || r.Pink < 0) {
+			return fmt.Errorf("seat %d starts with negative crystals", i+1)
+		}
+	}
+	if err := checkCardNames(s.ActionCards, isActionCardName); err != nil {
+		return fmt.Errorf("action cards: %w", err)
+	}
+	if err := checkCardNames(s.PointCards, isGolemName); err != nil {
+		return fmt.Errorf("point cards: %w", err)
+	}
+	if len(s.Coins) > len(CreateCoinCards()) {
+		return fmt.Errorf("there are only %d coin piles", len(CreateCoinCards()))
+	}
+	for _, n := range s.Coins {
+		if n < 0 {
+			return fmt.Errorf("coin piles cannot be negative")
+		}
+	}
+	if s.Rules.MarketActionCards < 0 || s.Rules.MarketPointCards < 0 || s.Rules.GolemsToEnd < 0 {
+		return fmt.Errorf("rules cannot be negative")
+	}
+	return nil
+}
+
+func checkCardNames(names []string, valid func(string) bool) error {
+	for _, name := range names {
+		if !valid(name) {
+			return fmt.Errorf("unknown card %q", name)
+		}
+	}
+	return nil
+}
+
+// actionCardName matches the action card names the game deals: mint_PBGY,
+// upgrade_2 or upgrade_3, and trade_PBGY_PBGY (crystal counts pink to yellow)
+var actionCardName = regexp.MustCompile(`^(mint_\d{4}|upgrade_[23]|trade_\d{4}_\d{4})$`)
+
+// isActionCardName accepts a well-formed action card name whose card does something:
+// a mint must make crystals and a trade must take and give some
+func isActionCardName(name string) bool {
+	if !actionCardName.MatchString(name) {
+		return false
+	}
+	card := CreateCardFromName(name, 0)
+	switch card.ActionType {
+	case Produce:
+		return card.Output != nil && card.Output.Total() > 0
+	case Trade:
+		return card.Input != nil && card.Output != nil && card.Input.Total() > 0 && card.Output.Total() > 0
+	}
+	return card.TurnUpgrade > 0
+}
+
+func isGolemName(name string) bool {
+	return strings.HasPrefix(name, "golem_") && CreateCardFromName(name, 0).Requirement != nil
+}
+
+// NewGame sets up the game the setup describes
+func (s GameSetup) NewGame() (*GameState, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s.newGame(), nil
+}
+
+// newGame sets up the game without validating the setup
+func (s GameSetup) newGame() *GameState {
+	rng := rand.New(rand.NewSource(s.Seed))
+	numPlayers := s.seats()
+
+	players := make([]*Player, numPlayers)
+	for i := range players {
+		var seat SeatSetup
+		if i < len(s.Seats) {
+			seat = s.Seats[i]
+		}
+		name := seat.Name
+		if name == "" {
+			name = fmt.Sprintf("Player %d", i+1)
+		}
+		players[i] = NewPlayer(i+1, name, false)
+		if seat.Resources != nil {
+			players[i].Resources = seat.Resources.Copy()
+		} else {
+			players[i].Resources = startingResources(i + 1)
+		}
+		if len(seat.Hand) > 0 {
+			players[i].Hand = cardsNamed(seat.Hand, 1000+100*i)
+		} else {
+			players[i].Hand = append(players[i].Hand, CreateInitialActionCards(i)...)
+		}
+	}
+
+	// Decks are shuffled in this order so a default setup deals like it always has
+	actionDeck := cardsNamed(s.ActionCards, 0)
+	if len(s.ActionCards) == 0 {
+		actionDeck = shuffleCards(CreateDefaultActionCards(), rng)
+	}
+	pointDeck := cardsNamed(s.PointCards, 100)
+	if len(s.PointCards) == 0 {
+		pointDeck = shuffleCards(CreateDefaultPointCards(), rng)
+	}
+
+	visibleActions, visiblePoints := marketSize(numPlayers)
+	if s.Rules.MarketActionCards > 0 {
+		visibleActions = s.Rules.MarketActionCards
+	}
+	if s.Rules.MarketPointCards > 0 {
+		visiblePoints = s.Rules.MarketPointCards
+	}
+	market := NewOrderedMarket(actionDeck, pointDeck, CreateCoinCards(), visibleActions, visiblePoints, coinsPerPlayer*numPlayers)
+	if len(s.Coins) > 0 {
+		market.SetCoins(s.Coins)
+	}
+
+	golemsToEnd := s.Rules.GolemsToEnd
+	if golemsToEnd == 0 {
+		golemsToEnd = defaultGolemsToEnd
+	}
+
+	return &GameState{
+		Players:     players,
+		Market:      market,
+		CurrentTurn: 0,
+		Round:       1,
+		GolemsToEnd: golemsToEnd,
+		RNG:         rng,
+	}
+}
+
+// startingResources returns the crystals a seat starts with
+func startingResources(seat int) *Resources {
+	// Player 1: 3 yellow
+	// Player 2: 4 yellow
+	// Player 3: 4 yellow
+	// Player 4: 3 yellow + 1 green
+	// Player 5: 3 yellow + 1 green
+	// Any further seat: 3 yellow
+	switch seat {
+	case 2, 3:
+		return &Resources{Yellow: 4}
+	case 4, 5:
+		return &Resources{Yellow: 3, Green: 1}
+	default:
+		return &Resources{Yellow: 3}
+	}
+}
+
+// cardsNamed creates the named cards with IDs counting up from firstID+1
+func cardsNamed(names []string, firstID int) []*Card {
+	cards := make([]*Card, len(names))
+	for i, name := range names {
+		cards[i] = CreateCardFromName(name, firstID+i+1)
+	}
+	return cards
+}
+
+// shuffleCards shuffles a copy of cards (Fisher-Yates)
+func shuffleCards(cards []*Card, rng *rand.Rand) []*Card {
+	shuffled := make([]*Card, len(cards))
+	copy(shuffled, cards)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	return shuffled
+}
@@ -0,0 +1,148 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func names(cards []*Card) []string {
+	result := make([]string, len(cards))
+	for i, card := range cards {
+		result[i] = card.Name
+	}
+	return result
+}
+
+const scenario = `{
+  "seed": 7,
+  "seats": [
+    {"name": "Ann", "resources": {"yellow": 2, "pink": 1}, "hand": ["upgrade_3", "mint_0002"]},
+    {"name": "Bob", "hand": ["trade_0002_0020"]},
+    {}
+  ],
+  "actionCards": ["mint_0011", "trade_0020_0200", "upgrade_2", "mint_0100", "trade_0003_1000", "mint_0002", "upgrade_3"],
+  "pointCards": ["golem_2022", "golem_0202", "golem_1111", "golem_0040", "golem_0222", "golem_0004"],
+  "coins": [1, 4],
+  "rules": {"marketActionCards": 4, "golemsToEnd": 3}
+}`
+
+// A scenario file deals exactly the hands, market and decks it lists
+func TestLoadSetupRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenario.json")
+	if err := os.WriteFile(path, []byte(scenario), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setup, err := LoadSetup(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setup.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	gs, err := setup.NewGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seats := []struct {
+		name      string
+		resources Resources
+		hand      []string
+	}{
+		{"Ann", Resources{Yellow: 2, Pink: 1}, []string{"upgrade_3", "mint_0002"}},
+		{"Bob", Resources{Yellow: 4}, []string{"trade_0002_0020"}},
+		{"Player 3", Resources{Yellow: 4}, names(CreateInitialActionCards(2))},
+	}
+	if len(gs.Players) != len(seats) {
+		t.Fatalf("got %d seats, want %d", len(gs.Players), len(seats))
+	}
+	for i, want := range seats {
+		p := gs.Players[i]
+		if p.Name != want.name || *p.Resources != want.resources || !reflect.DeepEqual(names(p.Hand), want.hand) {
+			t.Errorf("seat %d: got %s %+v %v, want %s %+v %v", i+1, p.Name, *p.Resources, names(p.Hand), want.name, want.resources, want.hand)
+		}
+	}
+
+	checks := []struct {
+		what      string
+		got, want []string
+	}{
+		{"action row", names(gs.Market.ActionCards), []string{"mint_0011", "trade_0020_0200", "upgrade_2", "mint_0100"}},
+		{"action deck", names(gs.Market.ActionDeck), []string{"trade_0003_1000", "mint_0002", "upgrade_3"}},
+		{"golem row", names(gs.Market.PointCards), []string{"golem_2022", "golem_0202", "golem_1111", "golem_0040", "golem_0222"}},
+		{"golem deck", names(gs.Market.PointDeck), []string{"golem_0004"}},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.what, c.got, c.want)
+		}
+	}
+	if len(gs.Market.Coins) != 2 || gs.Market.Coins[0].Amount != 1 || gs.Market.Coins[1].Amount != 4 {
+		t.Errorf("coin piles: got %v", gs.Market.Coins)
+	}
+	if gs.GolemsToEnd != 3 {
+		t.Errorf("golems to end: got %d, want 3", gs.GolemsToEnd)
+	}
+}
+
+// NewGameState skips Validate, so check the default setups would pass it
+func TestDefaultSetupValidates(t *testing.T) {
+	for n := MinPlayers; n <= MaxPlayers; n++ {
+		setup := GameSetup{NumPlayers: n, Seed: 1}
+		if err := setup.Validate(); err != nil {
+			t.Errorf("%d players: %v", n, err)
+			continue
+		}
+		gs, err := setup.NewGame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := NewGameState(n, 1)
+		if !reflect.DeepEqual(names(gs.Market.ActionCards), names(want.Market.ActionCards)) ||
+			!reflect.DeepEqual(names(gs.Market.PointCards), names(want.Market.PointCards)) {
+			t.Errorf("%d players: NewGame and NewGameState deal different markets", n)
+		}
+	}
+}
+
+func TestScenarioFilesValidate(t *testing.T) {
+	paths, err := filepath.Glob("../../docs/scenarios/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		setup, err := LoadSetup(path)
+		if err == nil {
+			_, err = setup.NewGame()
+		}
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
+
+func TestActionCardNames(t *testing.T) {
+	valid := append(names(CreateDefaultActionCards()), names(CreateInitialActionCards(0))...)
+	invalid := []string{
+		"mint_xyz", "mint_0000", "mint_002", "mint_0002_0002",
+		"upgrade_99", "upgrade_1", "upgrade_", "upgrade_x",
+		"trade_", "trade_0002", "trade_0000_0020", "trade_0002_0000", "trade_00x2_0020",
+		"golem_0022", "coin_3", "",
+	}
+	for _, name := range valid {
+		if !isActionCardName(name) {
+			t.Errorf("%q rejected", name)
+		}
+	}
+	for _, name := range invalid {
+		if isActionCardName(name) {
+			t.Errorf("%q accepted", name)
+		}
+		setup := GameSetup{NumPlayers: 2, ActionCards: []string{name}}
+		if err := setup.Validate(); err == nil {
+			t.Errorf("a setup dealing %q validates", name)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PlayerStats tracks what a player did over a game
+type PlayerStats struct {
+	Turns            int       `json:"turns"`            // Turns taken
+	CardsAcquired    int       `json:"cardsAcquired"`    // Action cards taken from the market
+	GolemsClaimed    int       `json:"golemsClaimed"`    // Point cards claimed
+	CrystalsProduced Resources `json:"crystalsProduced"` // Crystals gained by playing cards, by color
+	Rests            int       `json:"rests"`            // Times the player rested
+	DiscardTurns     int       `json:"discardTurns"`     // Turns spent discarding over the crystal limit
+}
+
+// recordProduced adds the crystals gained between before and after to the stats
+func (s *PlayerStats) recordProduced(before, after *Resources) {
+	for _, crystal := range []CrystalType{Yellow, Green, Blue, Pink} {
+		if gained := after.Get(crystal) - before.Get(crystal); gained > 0 {
+			s.CrystalsProduced.Add(crystal, gained)
+		}
+	}
+}
+
+// recordAction updates the current player's stats after action succeeded
+func (gs *GameState) recordAction(player *Player, action Action, before *Resources) {
+	switch action.Type {
+	case PlayCard:
+		player.Stats.recordProduced(before, player.Resources)
+	case AcquireCard:
+		player.Stats.CardsAcquired++
+	case ClaimPointCard:
+		player.Stats.GolemsClaimed++
+	case Rest:
+		player.Stats.Rests++
+	case DiscardCrystals:
+		player.Stats.DiscardTurns++
+	}
+	if EndsTurn(action.Type) {
+		player.Stats.Turns++
+	}
+}
+
+// EndsTurn reports whether an action type ends the player's turn.
+// Deposits and collections are intermediate steps before acquiring a card.
+func EndsTurn(actionType PlayerActionType) bool {
+	return actionType != DepositCrystals && actionType != CollectCrystals && actionType != CollectAllCrystals
+}
+
+// DescribeAction returns a readable description of an action by the current player.
+// Call it before ExecuteAction, while card indexes still refer to the current hand and market.
+func (gs *GameState) DescribeAction(action Action) string {
+	player := gs.GetCurrentPlayer()
+	switch action.Type {
+	case PlayCard:
+		if action.CardIndex >= 0 && action.CardIndex < len(player.Hand) {
+			card := player.Hand[action.CardIndex]
+			switch {
+			case card.ActionType == Upgrade && action.InputResources != nil && action.OutputResources != nil:
+				return fmt.Sprintf("Play Card: %s (%s -> %s)", card.Name, action.InputResources.String(), action.OutputResources.String())
+			case card.ActionType == Trade && action.Multiplier > 1:
+				return fmt.Sprintf("Play Card: %s x%d", card.Name, action.Multiplier)
+			}
+			return fmt.Sprintf("Play Card: %s", card.Name)
+		}
+		return "Play Card (invalid index)"
+	case AcquireCard:
+		if action.CardIndex >= 0 && action.CardIndex < len(gs.Market.ActionCards) {
+			card := gs.Market.ActionCards[action.CardIndex]
+			cost := gs.Market.GetActionCardCost(action.CardIndex)
+			return fmt.Sprintf("Acquire Card: %s (Cost: %s)", card.Name, cost.String())
+		}
+		return "Acquire Card (invalid index)"
+	case ClaimPointCard:
+		if action.CardIndex >= 0 && action.CardIndex < len(gs.Market.PointCards) {
+			card := gs.Market.PointCards[action.CardIndex]
+			return fmt.Sprintf("Claim Point Card: %s (%d points)", card.Name, card.Points)
+		}
+		return "Claim Point Card (invalid index)"
+	case Rest:
+		return "Rest (return all played cards to hand)"
+	case DiscardCrystals:
+		if action.Discard != nil {
+			return fmt.Sprintf("Discard Crystals: %s", action.Discard.String())
+		}
+		return "Discard Crystals"
+	case DepositCrystals:
+		return fmt.Sprintf("Deposit Crystals before market card %d", action.CardIndex-len(player.Hand))
+	case CollectCrystals:
+		positions := make([]string, len(action.CollectPositions))
+		for i, pos := range action.CollectPositions {
+			positions[i] = fmt.Sprintf("%d", pos)
+		}
+		return fmt.Sprintf("Collect Crystals from card %d (positions %s)", action.CardIndex, strings.Join(positions, ", "))
+	case CollectAllCrystals:
+		return fmt.Sprintf("Collect All Crystals from card %d", action.CardIndex)
+	default:
+		return "Unknown Action"
+	}
+}
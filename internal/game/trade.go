@@ -0,0 +1,50 @@
+package game
+
+// TradeOption is one multiplier a trade card can be played with
+type TradeOption struct {
+	Multiplier int        `json:"multiplier"`
+	Resources  *Resources `json:"resources"` // The player's crystals after the trade
+}
+
+// TradePlan lists how many times a trade card in hand can be played at once
+type TradePlan struct {
+	CardIndex     int           `json:"cardIndex"`
+	MaxMultiplier int           `json:"maxMultiplier"` // 0 when the player cannot pay even once
+	Options       []TradeOption `json:"options"`       // Multipliers 1..MaxMultiplier
+}
+
+// MaxTradeMultiplier returns how many times over resources can pay the trade card's input
+func MaxTradeMultiplier(resources *Resources, card *Card) int {
+	if card.Type != ActionCard || card.ActionType != Trade || card.Input == nil || card.Input.Total() == 0 {
+		return 0
+	}
+	max := -1
+	for _, crystal := range []CrystalType{Yellow, Green, Blue, Pink} {
+		if need := card.Input.Get(crystal); need > 0 {
+			if n := resources.Get(crystal) / need; max < 0 || n < max {
+				max = n
+			}
+		}
+	}
+	return max
+}
+
+// PlanTrades returns a plan for every trade card in the player's hand, in hand order
+func PlanTrades(player *Player) []TradePlan {
+	plans := make([]TradePlan, 0)
+	for i, card := range player.Hand {
+		if card.Type != ActionCard || card.ActionType != Trade {
+			continue
+		}
+		plan := TradePlan{CardIndex: i, MaxMultiplier: MaxTradeMultiplier(player.Resources, card)}
+		plan.Options = make([]TradeOption, plan.MaxMultiplier)
+		for m := 1; m <= plan.MaxMultiplier; m++ {
+			after := player.Resources.Copy()
+			after.SubtractAll(card.Input, m)
+			after.AddAll(card.Output, m)
+			plan.Options[m-1] = TradeOption{Multiplier: m, Resources: after}
+		}
+		plans = append(plans, plan)
+	}
+	return plans
+}
@@ -0,0 +1,255 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golem_century/internal/game"
+)
+
+// ErrNotFound is returned when no archived game has the requested ID
+var ErrNotFound = errors.New("game not found")
+
+// LogEntry is one action taken during a game
+type LogEntry struct {
+	Turn     int       `json:"turn"`
+	Round    int       `json:"round"`
+	PlayerID int       `json:"playerID"`
+	Player   string    `json:"player"`
+	Action   string    `json:"action"`
+	Time     time.Time `json:"time"`
+}
+
+// ScoreBreakdown splits a player's final score by source
+type ScoreBreakdown struct {
+	Golems   int `json:"golems"`
+	Coins    int `json:"coins"`
+	Crystals int `json:"crystals"` // Non-yellow crystals left at the end
+	Total    int `json:"total"`
+}
+
+// Participant is one seat in an archived game
+type Participant struct {
+	Seat      int              `json:"seat"`
+	Name      string           `json:"name"`
+	UserID    string           `json:"userID,omitempty"`
+	Placement int              `json:"placement"`
+	Score     ScoreBreakdown   `json:"score"`
+	Golems    []string         `json:"golems"`
+	Stats     game.PlayerStats `json:"stats"`
+}
+
+// Record is an archived, completed game
+type Record struct {
+	ID              string        `json:"id"`
+	SessionID       string        `json:"sessionID"`
+	Rated           bool          `json:"rated"`
+	StartedAt       time.Time     `json:"startedAt"`
+	EndedAt         time.Time     `json:"endedAt"`
+	DurationSeconds int64         `json:"durationSeconds"`
+	Turns           int           `json:"turns"`
+	Rounds          int           `json:"rounds"`
+	Participants    []Participant `json:"participants"`
+	Log             []LogEntry    `json:"log"`
+}
+
+// Summary is a Record without the action log and stats, for listings
+type Summary struct {
+	ID              string        `json:"id"`
+	SessionID       string        `json:"sessionID"`
+	Rated           bool          `json:"rated"`
+	EndedAt         time.Time     `json:"endedAt"`
+	DurationSeconds int64         `json:"durationSeconds"`
+	Turns           int           `json:"turns"`
+	Participants    []SummarySeat `json:"participants"`
+}
+
+// SummarySeat is a participant in a Summary
+type SummarySeat struct {
+	Seat      int    `json:"seat"`
+	Name      string `json:"name"`
+	Placement int    `json:"placement"`
+	Points    int    `json:"points"`
+}
+
+// NewRecord builds an archive record from a finished game.
+// users maps seat -> account ID for signed-in players.
+func NewRecord(sessionID string, state *game.GameState, users map[int]string, rated bool, startedAt time.Time, log []LogEntry) *Record {
+	endedAt := time.Now()
+	scores := make([]int, len(state.Players))
+	for i, p := range state.Players {
+		scores[i] = p.GetFinalPoints()
+	}
+
+	participants := make([]Participant, len(state.Players))
+	for i, p := range state.Players {
+		golems := make([]string, len(p.PointCards))
+		golemPoints := 0
+		for j, card := range p.PointCards {
+			golems[j] = card.Name
+			golemPoints += card.Points
+		}
+		coinPoints := 0
+		for _, coin := range p.Coins {
+			coinPoints += coin.Points
+		}
+		placement := 1
+		for _, other := range scores {
+			if other > scores[i] {
+				placement++
+			}
+		}
+		participants[i] = Participant{
+			Seat:      p.ID,
+			Name:      p.Name,
+			UserID:    users[p.ID],
+			Placement: placement,
+			Score: ScoreBreakdown{
+				Golems:   golemPoints,
+				Coins:    coinPoints,
+				Crystals: p.Resources.GetFinalPoints(),
+				Total:    scores[i],
+			},
+			Golems: golems,
+			Stats:  p.Stats,
+		}
+	}
+
+	return &Record{
+		ID:              fmt.Sprintf("%s-%d", sessionID, endedAt.UnixNano()),
+		SessionID:       sessionID,
+		Rated:           rated,
+		StartedAt:       startedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: int64(endedAt.Sub(startedAt).Seconds()),
+		Turns:           state.CurrentTurn + 1,
+		Rounds:          state.Round,
+		Participants:    participants,
+		Log:             log,
+	}
+}
+
+// Summary returns the listing view of a record
+func (r *Record) Summary() Summary {
+	seats := make([]SummarySeat, len(r.Participants))
+	for i, p := range r.Participants {
+		seats[i] = SummarySeat{Seat: p.Seat, Name: p.Name, Placement: p.Placement, Points: p.Score.Total}
+	}
+	return Summary{
+		ID:              r.ID,
+		SessionID:       r.SessionID,
+		Rated:           r.Rated,
+		EndedAt:         r.EndedAt,
+		DurationSeconds: r.DurationSeconds,
+		Turns:           r.Turns,
+		Participants:    seats,
+	}
+}
+
+// Store archives games as one JSON file each, keeping summaries in memory
+type Store struct {
+	dir       string
+	summaries map[string]*Record // ID -> record without its log (loaded lazily)
+	mu        sync.RWMutex
+}
+
+// Open loads (or creates) the archive in dir
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	s := &Store{dir: dir, summaries: make(map[string]*Record)}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		record, err := readRecord(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		record.Log = nil
+		s.summaries[record.ID] = record
+	}
+	return s, nil
+}
+
+// Add archives a finished game
+func (s *Store) Add(record *Record) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, record.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	summary := *record
+	summary.Log = nil
+	s.mu.Lock()
+	s.summaries[record.ID] = &summary
+	s.mu.Unlock()
+	return nil
+}
+
+// Get returns the full record for a game, including its action log
+func (s *Store) Get(id string) (*Record, error) {
+	s.mu.RLock()
+	_, ok := s.summaries[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return readRecord(filepath.Join(s.dir, id+".json"))
+}
+
+// ForPlayer returns games where player matches a seat's account ID or name
+// (case-insensitive), newest first
+func (s *Store) ForPlayer(player string, limit int) []Summary {
+	s.mu.RLock()
+	matches := make([]*Record, 0)
+	for _, record := range s.summaries {
+		for _, p := range record.Participants {
+			if (p.UserID != "" && p.UserID == player) || strings.EqualFold(p.Name, player) {
+				matches = append(matches, record)
+				break
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].EndedAt.After(matches[j].EndedAt) })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	result := make([]Summary, len(matches))
+	for i, record := range matches {
+		result[i] = record.Summary()
+	}
+	return result
+}
+
+func readRecord(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
@@ -0,0 +1,57 @@
+package outbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Outbox writes outgoing messages (emails, notifications) as files in a directory.
+// It stands in for a mail server so links and notifications can be tested offline.
+type Outbox struct {
+	Dir string
+	seq atomic.Int64
+}
+
+// New creates an outbox writing into dir, creating it if needed
+func New(dir string) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Outbox{Dir: dir}, nil
+}
+
+// Send writes a message and returns the path of the file created
+func (o *Outbox) Send(to, subject, body string) (string, error) {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%04d-%s.txt", now.Format("20060102T150405"), o.seq.Add(1)%10000, sanitize(to))
+	path := filepath.Join(o.Dir, name)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "To: %s\n", to)
+	fmt.Fprintf(&b, "Subject: %s\n", subject)
+	fmt.Fprintf(&b, "Date: %s\n\n", now.Format(time.RFC1123Z))
+	b.WriteString(body)
+	if !strings.HasSuffix(body, "\n") {
+		b.WriteString("\n")
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// sanitize keeps a recipient usable as part of a file name
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,250 @@
+package rating
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// InitialRating is the rating of a new account or bot
+	InitialRating = 1500.0
+	// KFactor is the maximum rating change from one game
+	KFactor    = 32.0
+	ratingFile = "ratings.json"
+)
+
+// Kinds of rated participants
+const (
+	KindPlayer = "player"
+	KindBot    = "bot"
+)
+
+// PlayerKey returns the rating key for an account
+func PlayerKey(userID string) string {
+	return KindPlayer + ":" + userID
+}
+
+// BotKey returns the rating key for a bot strategy
+func BotKey(strategy string) string {
+	return KindBot + ":" + strategy
+}
+
+// Rating is the current rating of an account or bot strategy
+type Rating struct {
+	Key       string    `json:"key"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	Rating    float64   `json:"rating"`
+	Games     int       `json:"games"`
+	Wins      int       `json:"wins"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Participant is one seat's result in a finished game
+type Participant struct {
+	Key       string // PlayerKey or BotKey
+	Name      string // Display name for the leaderboard
+	Placement int    // 1 = first; equal placements are ties
+}
+
+// Store keeps ratings and persists them to a JSON file.
+// A Store with an empty dir only keeps ratings in memory.
+type Store struct {
+	path    string
+	ratings map[string]*Rating
+	mu      sync.RWMutex
+}
+
+// Open loads (or creates) the rating store in dir
+func Open(dir string) (*Store, error) {
+	s := &Store{ratings: make(map[string]*Rating)}
+	if dir == "" {
+		return s, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	s.path = filepath.Join(dir, ratingFile)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ratings []*Rating
+	if err := json.Unmarshal(data, &ratings); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", s.path, err)
+	}
+	for _, r := range ratings {
+		s.ratings[r.Key] = r
+	}
+	return s, nil
+}
+
+// Get returns the rating for key (InitialRating if unrated)
+func (s *Store) Get(key string) Rating {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if r, ok := s.ratings[key]; ok {
+		return *r
+	}
+	return Rating{Key: key, Kind: kindOf(key), Rating: InitialRating}
+}
+
+// RecordGame updates ratings from final placements using multiplayer Elo:
+// every pair of participants is scored as a head-to-head game (win, draw or loss),
+// with K split across the n-1 opponents. Seats sharing a key (mirror bots) count
+// as one participant with their best placement, so each key plays the game once.
+func (s *Store) RecordGame(participants []Participant) error {
+	participants = mergeByKey(participants)
+	if len(participants) < 2 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := make([]float64, len(participants))
+	for i, p := range participants {
+		current[i] = s.lookup(p).Rating
+	}
+
+	k := KFactor / float64(len(participants)-1)
+	deltas := make([]float64, len(participants))
+	for i := range participants {
+		for j := range participants {
+			if i == j {
+				continue
+			}
+			expected := 1 / (1 + math.Pow(10, (current[j]-current[i])/400))
+			score := 0.5
+			if participants[i].Placement < participants[j].Placement {
+				score = 1
+			} else if participants[i].Placement > participants[j].Placement {
+				score = 0
+			}
+			deltas[i] += k * (score - expected)
+		}
+	}
+
+	now := time.Now()
+	for i, p := range participants {
+		r := s.lookup(p)
+		r.Rating += deltas[i]
+		r.Games++
+		if p.Placement == 1 {
+			r.Wins++
+		}
+		if p.Name != "" {
+			r.Name = p.Name
+		}
+		r.UpdatedAt = now
+	}
+	return s.save()
+}
+
+// mergeByKey folds seats with the same key into one participant with the best
+// placement, keeping the order in which keys first appear
+func mergeByKey(participants []Participant) []Participant {
+	merged := make([]Participant, 0, len(participants))
+	index := make(map[string]int, len(participants))
+	for _, p := range participants {
+		i, ok := index[p.Key]
+		if !ok {
+			index[p.Key] = len(merged)
+			merged = append(merged, p)
+			continue
+		}
+		if p.Placement < merged[i].Placement {
+			merged[i].Placement = p.Placement
+		}
+		if merged[i].Name == "" {
+			merged[i].Name = p.Name
+		}
+	}
+	return merged
+}
+
+// Leaderboard returns the top ratings of a kind ("" for all), best first
+func (s *Store) Leaderboard(kind string, limit int) []Rating {
+	s.mu.RLock()
+	result := make([]Rating, 0, len(s.ratings))
+	for _, r := range s.ratings {
+		if kind == "" || r.Kind == kind {
+			result = append(result, *r)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Rating != result[j].Rating {
+			return result[i].Rating > result[j].Rating
+		}
+		return result[i].Key < result[j].Key
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// lookup returns the stored rating for a participant, creating it (caller must hold s.mu)
+func (s *Store) lookup(p Participant) *Rating {
+	r, ok := s.ratings[p.Key]
+	if !ok {
+		r = &Rating{Key: p.Key, Name: p.Name, Kind: kindOf(p.Key), Rating: InitialRating}
+		s.ratings[p.Key] = r
+	}
+	return r
+}
+
+// save writes all ratings to disk atomically (caller must hold s.mu)
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	ratings := make([]*Rating, 0, len(s.ratings))
+	for _, r := range s.ratings {
+		ratings = append(ratings, r)
+	}
+	sort.Slice(ratings, func(i, j int) bool { return ratings[i].Key < ratings[j].Key })
+	data, err := json.MarshalIndent(ratings, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func kindOf(key string) string {
+	kind, _, _ := strings.Cut(key, ":")
+	return kind
+}
+
+// Placements converts final scores (in seat order) into placements, 1 = best.
+// Equal scores share a placement.
+func Placements(scores []int) []int {
+	placements := make([]int, len(scores))
+	for i, score := range scores {
+		placements[i] = 1
+		for _, other := range scores {
+			if other > score {
+				placements[i]++
+			}
+		}
+	}
+	return placements
+}
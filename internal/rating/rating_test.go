@@ -0,0 +1,107 @@
+package rating
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPlacements(t *testing.T) {
+	tests := []struct {
+		scores []int
+		want   []int
+	}{
+		{[]int{10, 20, 5}, []int{2, 1, 3}},
+		{[]int{7, 7}, []int{1, 1}},
+		{[]int{3, 9, 9, 1}, []int{3, 1, 1, 4}},
+		{[]int{4, 4, 2, 2}, []int{1, 1, 3, 3}},
+		{nil, []int{}},
+	}
+	for _, tt := range tests {
+		if got := Placements(tt.scores); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Placements(%v) = %v, want %v", tt.scores, got, tt.want)
+		}
+	}
+}
+
+func TestRecordGame(t *testing.T) {
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ann, bob, cat := PlayerKey("ann"), PlayerKey("bob"), PlayerKey("cat")
+	err = s.RecordGame([]Participant{
+		{Key: ann, Name: "Ann", Placement: 1},
+		{Key: bob, Name: "Bob", Placement: 2},
+		{Key: cat, Name: "Cat", Placement: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, second, third := s.Get(ann), s.Get(bob), s.Get(cat)
+	if !(first.Rating > second.Rating && second.Rating > third.Rating) {
+		t.Errorf("ratings do not follow placements: %.1f, %.1f, %.1f", first.Rating, second.Rating, third.Rating)
+	}
+	if sum := first.Rating + second.Rating + third.Rating - 3*InitialRating; math.Abs(sum) > 1e-9 {
+		t.Errorf("deltas sum to %g, want 0", sum)
+	}
+	if first.Games != 1 || first.Wins != 1 || second.Wins != 0 || first.Name != "Ann" {
+		t.Errorf("ann = %+v, want 1 game and 1 win", first)
+	}
+
+	// Reloading from disk keeps the ratings
+	reopened, err := Open(filepath.Dir(s.path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Get(ann); got.Rating != first.Rating || got.Games != first.Games || got.Name != first.Name {
+		t.Errorf("reloaded %+v, want %+v", got, first)
+	}
+}
+
+func TestRecordGameTie(t *testing.T) {
+	s, _ := Open("")
+	ann, bob := PlayerKey("ann"), PlayerKey("bob")
+	if err := s.RecordGame([]Participant{{Key: ann, Placement: 1}, {Key: bob, Placement: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{ann, bob} {
+		if r := s.Get(key); r.Rating != InitialRating || r.Games != 1 || r.Wins != 1 {
+			t.Errorf("%s = %+v, want an unchanged rating and a shared win", key, r)
+		}
+	}
+}
+
+// Two seats played by the same bot strategy count as one game for that bot
+func TestRecordGameSameKey(t *testing.T) {
+	s, _ := Open("")
+	ann, greedy := PlayerKey("ann"), BotKey("greedy")
+	err := s.RecordGame([]Participant{
+		{Key: greedy, Name: "Greedy", Placement: 1},
+		{Key: ann, Name: "Ann", Placement: 2},
+		{Key: greedy, Name: "Greedy", Placement: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot, player := s.Get(greedy), s.Get(ann)
+	if bot.Games != 1 || bot.Wins != 1 {
+		t.Errorf("bot has %d games and %d wins, want 1 and 1", bot.Games, bot.Wins)
+	}
+	if player.Games != 1 || player.Rating >= InitialRating {
+		t.Errorf("ann = %+v, want one lost game", player)
+	}
+	if sum := bot.Rating + player.Rating - 2*InitialRating; math.Abs(sum) > 1e-9 {
+		t.Errorf("deltas sum to %g, want 0", sum)
+	}
+
+	// A game between seats of one strategy rates nothing
+	if err := s.RecordGame([]Participant{{Key: greedy, Placement: 1}, {Key: greedy, Placement: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Get(greedy); got.Games != 1 {
+		t.Errorf("mirror game counted: %d games", got.Games)
+	}
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"golem_century/internal/account"
+)
+
+const (
+	sessionCookieName = "golem_session"
+	sessionCookieTTL  = 30 * 24 * time.Hour
+)
+
+// userFromRequest returns the signed-in account ID from the session cookie ("" if none)
+func (gs *GameServer) userFromRequest(r *http.Request) string {
+	if gs.Accounts == nil || gs.Signer == nil {
+		return ""
+	}
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return ""
+	}
+	userID, err := gs.Signer.Verify(cookie.Value)
+	if err != nil {
+		return ""
+	}
+	if _, err := gs.Accounts.Get(userID); err != nil {
+		return ""
+	}
+	return userID
+}
+
+// startUserSession sets the signed session cookie for user
+func (gs *GameServer) startUserSession(w http.ResponseWriter, user *account.User) {
+	value := gs.Signer.Sign(user.ID, time.Now().Add(sessionCookieTTL))
+	http.SetCookie(w, gs.newCookie(sessionCookieName, value, sessionCookieTTL))
+}
+
+// accountsEnabled writes an error and returns false when the server has no account store
+func (gs *GameServer) accountsEnabled(w http.ResponseWriter) bool {
+	if gs.Accounts == nil || gs.Signer == nil {
+		sendJSONError(w, http.StatusServiceUnavailable, "Accounts are not enabled on this server")
+		return false
+	}
+	return true
+}
+
+// serializeUser returns the public view of an account
+func serializeUser(user *account.User) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        user.ID,
+		"username":  user.Username,
+		"createdAt": user.CreatedAt,
+		"results":   user.Results,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// HandleRegister creates a password account and signs it in
+func (gs *GameServer) HandleRegister(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !gs.accountsEnabled(w) {
+		return
+	}
+	// Registering hashes a password too, so it shares the sign-in limit
+	if !gs.loginLimiter.Allow(clientIP(r)) {
+		sendRateLimited(w, gs.loginLimiter.interval)
+		return
+	}
+
+	var req struct {
+		Username string `json:"username"`
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	user, err := gs.Accounts.Register(req.Username, req.Email, req.Password)
+	if errors.Is(err, account.ErrUsernameTaken) || errors.Is(err, account.ErrEmailTaken) {
+		sendJSONError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	gs.startUserSession(w, user)
+	writeJSON(w, serializeUser(user))
+}
+
+// HandleLogin signs in with a username and password
+func (gs *GameServer) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !gs.accountsEnabled(w) {
+		return
+	}
+	// Each attempt hashes a password, which is slow on purpose
+	if !gs.loginLimiter.Allow(clientIP(r)) {
+		sendRateLimited(w, gs.loginLimiter.interval)
+		return
+	}
+
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	user, err := gs.Accounts.Authenticate(req.Username, req.Password)
+	if err != nil {
+		sendJSONError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	gs.startUserSession(w, user)
+	writeJSON(w, serializeUser(user))
+}
+
+// HandleMagicLink sends a one-time sign-in link to an email address (via the outbox).
+// It needs PublicURL to build the link.
+func (gs *GameServer) HandleMagicLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !gs.accountsEnabled(w) {
+		return
+	}
+	// Links are built from the configured URL: the Host header is the client's to choose
+	if gs.PublicURL == "" {
+		sendJSONError(w, http.StatusServiceUnavailable, "Sign-in links are not enabled on this server")
+		return
+	}
+	// Sending links costs disk space, so it shares the session creation limit
+	if gs.createLimiter != nil && !gs.createLimiter.Allow(clientIP(r)) {
+		sendRateLimited(w, gs.createLimiter.interval)
+		return
+	}
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := gs.Accounts.SendMagicLink(req.Email, gs.PublicURL); err != nil {
+		sendJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, map[string]interface{}{"status": "sent"})
+}
+
+// HandleMagicLogin signs in from a magic link and redirects to the lobby
+func (gs *GameServer) HandleMagicLogin(w http.ResponseWriter, r *http.Request) {
+	if !gs.accountsEnabled(w) {
+		return
+	}
+	user, err := gs.Accounts.ConsumeMagicLink(r.URL.Query().Get("token"))
+	if err != nil {
+		sendJSONError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	gs.startUserSession(w, user)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// HandleLogout clears the session cookie
+func (gs *GameServer) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendJSONError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	http.SetCookie(w, gs.newCookie(sessionCookieName, "", -time.Second))
+	writeJSON(w, map[string]interface{}{"status": "ok"})
+}
+
+// HandleMe returns the signed-in account with its game history
+func (gs *GameServer) HandleMe(w http.ResponseWriter, r *http.Request) {
+	if !gs.accountsEnabled(w) {
+		return
+	}
+	userID := gs.userFromRequest(r)
+	if userID == "" {
+		sendJSONError(w, http.StatusUnauthorized, "Not signed in")
+		return
+	}
+	user, err := gs.Accounts.Get(userID)
+	if err != nil {
+		sendJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, serializeUser(user))
+}
+
+// recordAccountResults stores each signed-in seat's result on their account
+func (gs *GameServer) recordAccountResults(session *GameSession) {
+	if gs.Accounts == nil {
+		return
+	}
+	session.mu.RLock()
+	users := make(map[int]string, len(session.PlayerUsers))
+	for seat, userID := range session.PlayerUsers {
+		users[seat] = userID
+	}
+	session.mu.RUnlock()
+
+	standings := session.GameState.Standings()
+	now := time.Now()
+	for placement, player := range standings {
+		userID, ok := users[player.ID]
+		if !ok {
+			continue
+		}
+		result := account.GameResult{
+			SessionID:  session.ID,
+			Seat:       player.ID,
+			Placement:  placement + 1,
+			NumPlayers: len(standings),
+			Points:     player.GetFinalPoints(),
+			PlayedAt:   now,
+		}
+		if err := gs.Accounts.RecordResult(userID, result); err != nil {
+			log.Printf("Failed to record result for %s: %v", userID, err)
+		}
+	}
+}
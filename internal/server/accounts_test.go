@@ -0,0 +1,80 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golem_century/internal/account"
+	"golem_century/internal/outbox"
+)
+
+// newAccountServer returns a server with accounts kept in a temporary directory
+func newAccountServer(t *testing.T) *GameServer {
+	dir := t.TempDir()
+	mail, err := outbox.New(filepath.Join(dir, "outbox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := NewGameServer()
+	gs.Outbox = mail
+	if gs.Accounts, err = account.Open(dir, mail); err != nil {
+		t.Fatal(err)
+	}
+	gs.Signer = account.NewSigner([]byte(strings.Repeat("k", 32)))
+	return gs
+}
+
+func post(handler http.HandlerFunc, path, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+// Sign-in links point at the configured site, whatever Host the request names
+func TestMagicLinkUsesPublicURL(t *testing.T) {
+	gs := newAccountServer(t)
+	if w := post(gs.HandleMagicLink, "/api/magic-link", `{"email":"ann@example.com"}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("without a public URL: got %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	gs.PublicURL = "https://golem.example.com/"
+	r := httptest.NewRequest(http.MethodPost, "/api/magic-link", strings.NewReader(`{"email":"ann@example.com"}`))
+	r.Host = "attacker.example"
+	w := httptest.NewRecorder()
+	gs.HandleMagicLink(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d: %s", w.Code, w.Body)
+	}
+	sent, err := os.ReadDir(gs.Outbox.Dir)
+	if err != nil || len(sent) != 1 {
+		t.Fatalf("outbox holds %d mails (%v), want 1", len(sent), err)
+	}
+	body, err := os.ReadFile(filepath.Join(gs.Outbox.Dir, sent[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "https://golem.example.com/api/magic-login?token=") || strings.Contains(string(body), "attacker") {
+		t.Errorf("mail does not link to the public URL:\n%s", body)
+	}
+}
+
+func TestLoginRateLimited(t *testing.T) {
+	gs := newAccountServer(t)
+	for i := 0; i < loginBurst; i++ {
+		if w := post(gs.HandleLogin, "/api/login", `{"username":"ann","password":"wrong"}`); w.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: got %d, want %d", i+1, w.Code, http.StatusUnauthorized)
+		}
+	}
+	if w := post(gs.HandleLogin, "/api/login", `{"username":"ann","password":"wrong"}`); w.Code != http.StatusTooManyRequests {
+		t.Errorf("login after %d attempts: got %d, want %d", loginBurst, w.Code, http.StatusTooManyRequests)
+	}
+	// Registering shares the limit
+	if w := post(gs.HandleRegister, "/api/register", `{"username":"bob","email":"bob@example.com","password":"correct horse"}`); w.Code != http.StatusTooManyRequests {
+		t.Errorf("register after %d attempts: got %d, want %d", loginBurst, w.Code, http.StatusTooManyRequests)
+	}
+}
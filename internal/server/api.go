@@ -0,0 +1,193 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golem_century/internal/game"
+)
+
+// Errors from submitting an action outside the game rules themselves
+var (
+	errNotYourTurn = errors.New("not your turn")
+	errGameOver    = errors.New("game is over")
+	errGameBusy    = errors.New("game is busy, try again")
+)
+
+// Event is something that happened as a result of an action
+type Event struct {
+	Type     string `json:"type"` // action, lastRound, discardRequired, turn or gameOver
+	PlayerID int    `json:"playerID,omitempty"`
+	Action   string `json:"action,omitempty"` // Description of the action taken
+	Turn     int    `json:"turn,omitempty"`
+	Round    int    `json:"round,omitempty"`
+	Count    int    `json:"count,omitempty"`  // Crystals the player must discard
+	Points   int    `json:"points,omitempty"` // Winner's final points
+}
+
+// sendAPIError sends a JSON error with a machine-readable code alongside the message
+func sendAPIError(w http.ResponseWriter, statusCode int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  message,
+		"code":   code,
+		"status": "error",
+	})
+}
+
+// seatFromRequest authenticates a REST request by seat token
+// ("Authorization: Bearer <token>" or ?token=) and returns the seat (0 if invalid)
+func seatFromRequest(session *GameSession, r *http.Request) int {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	return session.SeatForToken(token)
+}
+
+// sessionFromPath looks up the session named in the request path
+func (gs *GameServer) sessionFromPath(w http.ResponseWriter, r *http.Request) (*GameSession, bool) {
+	sessionID := r.PathValue("id")
+	if !sessionIDPattern.MatchString(sessionID) {
+		sendAPIError(w, http.StatusBadRequest, "bad_request", "Invalid session ID")
+		return nil, false
+	}
+	session, ok := gs.GetSession(sessionID)
+	if !ok {
+		sendAPIError(w, http.StatusNotFound, "not_found", "Session not found")
+	}
+	return session, ok
+}
+
+// submitAction queues an action for playerID and waits for the game loop's verdict
+func (gs *GameSession) submitAction(playerID int, action game.Action) ([]Event, error) {
+	gs.mu.RLock()
+	gameOver := gs.GameState.GameOver
+	gs.mu.RUnlock()
+	if gameOver {
+		return nil, errGameOver
+	}
+	result := make(chan ActionResult, 1)
+	select {
+	case gs.ActionChan <- PlayerAction{PlayerID: playerID, Action: action, Result: result}:
+	case <-time.After(restActionTimeout):
+		return nil, errGameBusy
+	}
+	select {
+	case res := <-result:
+		return res.Events, res.Err
+	case <-time.After(restActionTimeout):
+		return nil, errGameBusy
+	}
+}
+
+// HandleGetState returns the game state for a seat (/api/games/{id}/state?player=N),
+// authenticated by that seat's token
+func (gs *GameServer) HandleGetState(w http.ResponseWriter, r *http.Request) {
+	session, ok := gs.sessionFromPath(w, r)
+	if !ok {
+		return
+	}
+	playerID := seatFromRequest(session, r)
+	if playerID == 0 {
+		sendAPIError(w, http.StatusUnauthorized, "unauthorized", "Invalid seat token")
+		return
+	}
+	if playerStr := r.URL.Query().Get("player"); playerStr != "" {
+		if requested, err := strconv.Atoi(playerStr); err != nil || requested != playerID {
+			sendAPIError(w, http.StatusForbidden, "forbidden", "Seat token does not belong to that player")
+			return
+		}
+	}
+
+	state := session.SerializeState()
+	session.mu.RLock()
+	player := session.GameState.Players[playerID-1]
+	state["you"] = playerID
+	state["yourTurn"] = !session.GameState.GameOver && session.GameState.GetCurrentPlayer().ID == playerID
+	state["pendingDiscard"] = player.PendingDiscard
+	session.mu.RUnlock()
+	writeJSON(w, state)
+}
+
+// HandleGetPaths returns the seat's shortest path to every market golem
+// (/api/games/{id}/paths), authenticated by that seat's token
+func (gs *GameServer) HandleGetPaths(w http.ResponseWriter, r *http.Request) {
+	session, ok := gs.sessionFromPath(w, r)
+	if !ok {
+		return
+	}
+	playerID := seatFromRequest(session, r)
+	if playerID == 0 {
+		sendAPIError(w, http.StatusUnauthorized, "unauthorized", "Invalid seat token")
+		return
+	}
+	paths, err := session.golemPaths(playerID)
+	if err != nil {
+		sendAPIError(w, http.StatusForbidden, "forbidden", err.Error())
+		return
+	}
+	writeJSON(w, map[string]interface{}{"paths": paths})
+}
+
+// HandleSubmitAction submits a move over REST, authenticated by seat token.
+// The body is the same message the WebSocket accepts ({"actionType": ..., ...});
+// the response lists the events the move caused, or an error with a code.
+func (gs *GameServer) HandleSubmitAction(w http.ResponseWriter, r *http.Request) {
+	session, ok := gs.sessionFromPath(w, r)
+	if !ok {
+		return
+	}
+	playerID := seatFromRequest(session, r)
+	if playerID == 0 {
+		sendAPIError(w, http.StatusUnauthorized, "unauthorized", "Invalid seat token")
+		return
+	}
+
+	var msg map[string]interface{}
+	r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		sendAPIError(w, http.StatusBadRequest, "bad_request", "Invalid request")
+		return
+	}
+	action, err := parseAction(msg)
+	if err != nil {
+		sendAPIError(w, http.StatusBadRequest, "invalid_action", err.Error())
+		return
+	}
+
+	events, err := session.submitAction(playerID, action)
+	switch {
+	case errors.Is(err, errNotYourTurn):
+		sendAPIError(w, http.StatusConflict, "not_your_turn", err.Error())
+		return
+	case errors.Is(err, errGameOver):
+		sendAPIError(w, http.StatusConflict, "game_over", err.Error())
+		return
+	case errors.Is(err, errGameBusy):
+		sendAPIError(w, http.StatusServiceUnavailable, "busy", err.Error())
+		return
+	case err != nil:
+		// The game rejected the move
+		sendAPIError(w, http.StatusUnprocessableEntity, "illegal_action", err.Error())
+		return
+	}
+
+	session.mu.RLock()
+	response := map[string]interface{}{
+		"status":        "ok",
+		"events":        events,
+		"currentPlayer": session.GameState.GetCurrentPlayer().ID,
+		"gameOver":      session.GameState.GameOver,
+	}
+	if session.Correspondence {
+		response["deadline"] = session.Deadline
+	}
+	session.mu.RUnlock()
+	writeJSON(w, response)
+}
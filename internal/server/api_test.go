@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// submit posts a move over REST with a seat token and returns the status and reply
+func submit(t *testing.T, url, token, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var reply map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&reply)
+	return resp.StatusCode, reply
+}
+
+// eventTypes lists the types of the events in a move's reply
+func eventTypes(reply map[string]interface{}) []string {
+	events, _ := reply["events"].([]interface{})
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		if e, ok := event.(map[string]interface{}); ok {
+			types = append(types, e["type"].(string))
+		}
+	}
+	return types
+}
+
+func TestSubmitAction(t *testing.T) {
+	gs := NewGameServer()
+	srv := newTestServer(t, gs)
+	session, err := gs.CreateSession("rest", 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session.mu.Lock()
+	session.GameState.LastRound = true // Seat 2's move ends the game
+	session.mu.Unlock()
+	url := srv.URL + "/api/games/rest/actions"
+	first, second := session.SeatToken(1), session.SeatToken(2)
+
+	errorTests := []struct {
+		name   string
+		token  string
+		body   string
+		status int
+		code   string
+	}{
+		{"no token", "", `{"actionType":"rest"}`, http.StatusUnauthorized, "unauthorized"},
+		{"wrong token", "nope", `{"actionType":"rest"}`, http.StatusUnauthorized, "unauthorized"},
+		{"bad JSON", first, `{"actionType":`, http.StatusBadRequest, "bad_request"},
+		{"unknown action", first, `{"actionType":"fly"}`, http.StatusBadRequest, "invalid_action"},
+		{"out of turn", second, `{"actionType":"rest"}`, http.StatusConflict, "not_your_turn"},
+		{"illegal move", first, `{"actionType":"claimPointCard","cardIndex":0}`, http.StatusUnprocessableEntity, "illegal_action"},
+	}
+	for _, tt := range errorTests {
+		status, reply := submit(t, url, tt.token, tt.body)
+		if status != tt.status || reply["code"] != tt.code {
+			t.Errorf("%s: got %d %v, want %d %q", tt.name, status, reply["code"], tt.status, tt.code)
+		}
+	}
+	if status, _ := submit(t, srv.URL+"/api/games/missing/actions", first, `{"actionType":"rest"}`); status != http.StatusNotFound {
+		t.Errorf("unknown game: got %d, want %d", status, http.StatusNotFound)
+	}
+
+	status, reply := submit(t, url, first, `{"actionType":"rest"}`)
+	if status != http.StatusOK || reply["currentPlayer"] != 2.0 || reply["gameOver"] != false {
+		t.Fatalf("seat 1 rests: got %d %v", status, reply)
+	}
+	if got := strings.Join(eventTypes(reply), ","); got != "action,turn" {
+		t.Errorf("seat 1 rests: events %s, want action,turn", got)
+	}
+
+	status, reply = submit(t, url, second, `{"actionType":"rest"}`)
+	if status != http.StatusOK || reply["gameOver"] != true {
+		t.Fatalf("seat 2 ends the game: got %d %v", status, reply)
+	}
+	if got := strings.Join(eventTypes(reply), ","); got != "action,gameOver" {
+		t.Errorf("seat 2 ends the game: events %s, want action,gameOver", got)
+	}
+
+	if status, reply := submit(t, url, first, `{"actionType":"rest"}`); status != http.StatusConflict || reply["code"] != "game_over" {
+		t.Errorf("move after the game: got %d %v, want %d game_over", status, reply["code"], http.StatusConflict)
+	}
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golem_century/internal/game"
+
+	"github.com/gorilla/websocket"
+)
+
+// BotMoveTimeout is how long a remote bot has to answer a turn request.
+// Late, invalid or missing answers make the bot rest.
+const BotMoveTimeout = 5 * time.Second
+
+// botReply is a bot's answer to a turn request
+type botReply struct {
+	RequestID int `json:"requestID"`
+	ActionID  int `json:"actionID"`
+}
+
+// legalActionView is one enumerated legal action sent to a bot
+type legalActionView struct {
+	ID          int         `json:"id"`
+	Action      game.Action `json:"action"`
+	Description string      `json:"description"`
+}
+
+// remoteBot plays a seat on behalf of a program connected to /ws/bot.
+// It implements game.Bot; ChooseAction runs on the session's game loop.
+type remoteBot struct {
+	conn      *websocket.Conn
+	writeMu   sync.Mutex
+	replies   chan botReply
+	closed    chan struct{}
+	requestID int
+}
+
+func newRemoteBot(conn *websocket.Conn) *remoteBot {
+	return &remoteBot{
+		conn:    conn,
+		replies: make(chan botReply, 8),
+		closed:  make(chan struct{}),
+	}
+}
+
+// send writes a message to the bot
+func (b *remoteBot) send(msg map[string]interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	b.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return b.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// ChooseAction sends the observation and legal actions, then waits for the bot's pick
+func (b *remoteBot) ChooseAction(player *game.Player, market *game.Market, gameState *game.GameState) game.Action {
+	rest := game.Action{Type: game.Rest}
+	legal := gameState.LegalActions()
+	views := make([]legalActionView, len(legal))
+	for i, action := range legal {
+		views[i] = legalActionView{ID: i, Action: action, Description: gameState.DescribeAction(action)}
+	}
+
+	b.requestID++
+	requestID := b.requestID
+	err := b.send(map[string]interface{}{
+		"type":         "turn",
+		"requestID":    requestID,
+		"timeoutMs":    BotMoveTimeout.Milliseconds(),
+		"observation":  gameState.Observe(player.ID),
+		"legalActions": views,
+	})
+	if err != nil {
+		return rest
+	}
+
+	timeout := time.NewTimer(BotMoveTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case reply := <-b.replies:
+			if reply.RequestID != requestID {
+				continue // Late answer to an earlier turn
+			}
+			if reply.ActionID < 0 || reply.ActionID >= len(legal) {
+				b.send(map[string]interface{}{"type": "error", "error": fmt.Sprintf("invalid action ID %d, resting", reply.ActionID)})
+				return rest
+			}
+			return legal[reply.ActionID]
+		case <-timeout.C:
+			b.send(map[string]interface{}{"type": "error", "error": "turn timed out, resting"})
+			return rest
+		case <-b.closed:
+			return rest
+		}
+	}
+}
+
+// HandleBotWebSocket seats an external bot (/ws/bot?session=...&name=...&token=...).
+// See docs/BOT_PROTOCOL.md for the message format.
+func (gs *GameServer) HandleBotWebSocket(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.URL.Query().Get("session")
+	name := r.URL.Query().Get("name")
+	if !sessionIDPattern.MatchString(sessionID) {
+		sendJSONError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		sendJSONError(w, http.StatusBadRequest, fmt.Sprintf("Name too long (max %d characters)", maxNameLength))
+		return
+	}
+	session, ok := gs.GetSession(sessionID)
+	if !ok {
+		sendJSONError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	// A seat token takes that (reserved) seat; otherwise the bot needs an open seat
+	playerID := session.SeatForToken(r.URL.Query().Get("token"))
+	if playerID == 0 {
+		if !session.CheckPasscode(r.URL.Query().Get("passcode")) {
+			sendJSONError(w, http.StatusForbidden, "Invalid passcode")
+			return
+		}
+		session.mu.RLock()
+		for i := 1; i <= len(session.GameState.Players); i++ {
+			if _, connected := session.Connections[i]; !connected && !session.LockedSeats[i] {
+				playerID = i
+				break
+			}
+		}
+		session.mu.RUnlock()
+		if playerID == 0 {
+			sendJSONError(w, http.StatusForbidden, "Game is full")
+			return
+		}
+	}
+	if name == "" {
+		name = fmt.Sprintf("Bot %d", playerID)
+	}
+
+	conn, err := gs.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxMessageSize)
+
+	bot := newRemoteBot(conn)
+	defer close(bot.closed)
+	// External bots are rated by name, apart from the server's own strategies
+	if err := session.AddBot(playerID, name, "remote:"+name, bot); err != nil {
+		bot.send(map[string]interface{}{"type": "error", "error": err.Error()})
+		return
+	}
+	session.BroadcastState()
+	bot.send(map[string]interface{}{
+		"type":       "welcome",
+		"sessionID":  session.ID,
+		"playerID":   playerID,
+		"seatToken":  session.SeatToken(playerID),
+		"numPlayers": len(session.GameState.Players),
+	})
+
+	// Tell the bot how the game ended, once the game loop has finished it
+	go func() {
+		select {
+		case <-bot.closed:
+		case <-session.done:
+			bot.send(map[string]interface{}{"type": "gameOver", "standings": session.standings})
+		}
+	}()
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var reply botReply
+		if err := json.Unmarshal(message, &reply); err != nil {
+			bot.send(map[string]interface{}{"type": "error", "error": "invalid message"})
+			continue
+		}
+		select {
+		case bot.replies <- reply:
+		default: // A bot flooding replies only loses the extras
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"golem_century/internal/game"
+)
+
+// A remote bot is told the standings when the game ends
+func TestRemoteBotGameOver(t *testing.T) {
+	gs := NewGameServer()
+	srv := newTestServer(t, gs)
+	session, err := gs.CreateSession("remote", 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session.mu.Lock()
+	session.GameState.LastRound = true // Seat 2's move ends the game
+	session.mu.Unlock()
+
+	bot, status := dial(t, srv, "/ws/bot?session=remote&name=Robo")
+	if bot == nil {
+		t.Fatalf("bot connect: got %d", status)
+	}
+	if msg := readType(t, bot, "welcome"); msg["playerID"] != 1.0 {
+		t.Fatalf("bot seated at %v, want seat 1", msg["playerID"])
+	}
+
+	turn := readType(t, bot, "turn")
+	legal, _ := turn["legalActions"].([]interface{})
+	restID := -1.0
+	for _, view := range legal {
+		v := view.(map[string]interface{})
+		if action, _ := v["action"].(map[string]interface{}); action["type"] == "rest" {
+			restID = v["id"].(float64)
+		}
+	}
+	if restID < 0 {
+		t.Fatalf("no rest among %d legal actions", len(legal))
+	}
+	if err := bot.WriteJSON(map[string]interface{}{"requestID": turn["requestID"], "actionID": restID}); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for currentSeat(session) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("the bot's move was never played")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	// Seat 2's move ends the game
+	if _, err := session.submitAction(2, game.Action{Type: game.Rest}); err != nil {
+		t.Fatal(err)
+	}
+	msg := readType(t, bot, "gameOver")
+	standings, _ := msg["standings"].([]interface{})
+	if len(standings) != 2 {
+		t.Errorf("gameOver standings %v, want both seats", msg["standings"])
+	}
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+	"unicode/utf8"
+
+	"golem_century/internal/game"
+)
+
+const (
+	botMoveDelay = 800 * time.Millisecond // Paces bot turns so humans can follow what happened
+	botThinkTime = 10 * time.Second       // How long a bot may think before it rests instead
+)
+
+// AddBot seats a server-side bot. The seat is locked so no human can take it.
+// Rated games rate the seat as rating.BotKey(strategy).
+func (gs *GameSession) AddBot(playerID int, name, strategy string, bot game.Bot) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if playerID < 1 || playerID > len(gs.GameState.Players) {
+		return fmt.Errorf("invalid seat %d", playerID)
+	}
+	if _, connected := gs.Connections[playerID]; connected {
+		return fmt.Errorf("seat %d is taken", playerID)
+	}
+	player := gs.GameState.Players[playerID-1]
+	player.Name = name
+	player.IsAI = true
+	gs.Bots[playerID] = bot
+	gs.BotStrategies[playerID] = strategy
+	gs.PlayerNames[playerID] = name
+	gs.LockedSeats[playerID] = true
+	return nil
+}
+
+// BotSeat asks for a server-side bot in a seat
+type BotSeat struct {
+	Seat int    `json:"seat"`
+	Name string `json:"name"` // Default: from the settings, e.g. "Hard Rusher Bot"
+	game.BotSettings
+}
+
+// botWeights returns the evaluation weights server bots use
+func (gs *GameServer) botWeights() game.Weights {
+	if gs.BotWeights != nil {
+		return *gs.BotWeights
+	}
+	return game.DefaultWeights()
+}
+
+// newBot creates a bot for a seat request. It returns the request normalized, with
+// the display name filled in.
+func (gs *GameServer) newBot(seat BotSeat) (game.Bot, BotSeat, error) {
+	settings, err := seat.BotSettings.Normalize()
+	if err != nil {
+		return nil, seat, err
+	}
+	bot, err := game.NewBot(settings, gs.botWeights(), rand.New(rand.NewSource(time.Now().UnixNano()+int64(seat.Seat))))
+	if err != nil {
+		return nil, seat, err
+	}
+	seat.BotSettings = settings
+	if seat.Name == "" {
+		seat.Name = settings.Name()
+	}
+	if utf8.RuneCountInString(seat.Name) > maxNameLength {
+		return nil, seat, fmt.Errorf("name too long (max %d characters)", maxNameLength)
+	}
+	return bot, seat, nil
+}
+
+// strategy is what the bot is rated as: its settings, whatever its display name
+func (seat BotSeat) strategy() string {
+	return seat.BotSettings.Name()
+}
+
+// hostAddBot seats a bot the host asked for. The seat must be free and unreserved.
+func (gs *GameServer) hostAddBot(session *GameSession, hostID int, seat BotSeat) error {
+	session.mu.RLock()
+	isHost := session.HostID == hostID
+	reserved := session.LockedSeats[seat.Seat]
+	session.mu.RUnlock()
+	if !isHost {
+		return fmt.Errorf("only the host can add bots")
+	}
+	if reserved {
+		return fmt.Errorf("seat %d is reserved", seat.Seat)
+	}
+
+	bot, seat, err := gs.newBot(seat)
+	if err != nil {
+		return err
+	}
+	return session.AddBot(seat.Seat, seat.Name, seat.strategy(), bot)
+}
+
+// playBotTurn starts a bot thinking if it holds the current seat (called from the game loop).
+// The bot thinks on its own goroutine so the loop keeps serving moves, chat and deadlines.
+func (gs *GameSession) playBotTurn() {
+	if gs.GameState.GameOver || gs.botThinking || time.Since(gs.lastMove) < botMoveDelay {
+		return
+	}
+	gs.mu.RLock()
+	player := gs.GameState.GetCurrentPlayer()
+	bot, ok := gs.Bots[player.ID]
+	var view *game.GameState
+	if ok {
+		// Bots think on a copy, so the game can go on meanwhile
+		view = gs.GameState.Clone()
+	}
+	gs.mu.RUnlock()
+	if !ok {
+		return
+	}
+	gs.botThinking = true
+	go gs.think(bot, player.ID, view)
+}
+
+// think lets a bot choose its move and posts it to the game loop through ActionChan.
+// A bot that takes longer than botThinkTime rests.
+func (gs *GameSession) think(bot game.Bot, playerID int, view *game.GameState) {
+	chosen := make(chan game.Action, 1)
+	go func() {
+		chosen <- bot.ChooseAction(view.GetCurrentPlayer(), view.Market, view)
+	}()
+
+	var action game.Action
+	select {
+	case action = <-chosen:
+	case <-time.After(botThinkTime):
+		log.Printf("Bot in %s seat %d took over %v to move, resting", gs.ID, playerID, botThinkTime)
+		action = game.Action{Type: game.Rest}
+	case <-gs.done:
+		return
+	}
+	select {
+	case gs.ActionChan <- PlayerAction{PlayerID: playerID, Action: action, bot: true, turn: view.CurrentTurn}:
+	case <-gs.done:
+	}
+}
+
+// playBotAction plays the move a bot chose (called from the game loop). A move for
+// a turn that has passed meanwhile, e.g. on a missed deadline, is dropped.
+func (gs *GameSession) playBotAction(action PlayerAction) {
+	gs.botThinking = false
+	player := gs.GameState.GetCurrentPlayer()
+	if action.PlayerID != player.ID || action.turn != gs.GameState.CurrentTurn {
+		return
+	}
+	if _, err := gs.applyAction(action.Action); err != nil {
+		// A bot must never stall the table; an illegal choice becomes a rest
+		log.Printf("Bot in %s seat %d chose an invalid action (%v), resting", gs.ID, player.ID, err)
+		if _, err := gs.applyAction(game.Action{Type: game.Rest}); err != nil {
+			log.Printf("Bot in %s seat %d could not rest: %v", gs.ID, player.ID, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golem_century/internal/game"
+)
+
+// slowBot rests once released
+type slowBot struct{ release chan struct{} }
+
+func (b slowBot) ChooseAction(*game.Player, *game.Market, *game.GameState) game.Action {
+	<-b.release
+	return game.Action{Type: game.Rest}
+}
+
+func currentSeat(session *GameSession) int {
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	return session.GameState.GetCurrentPlayer().ID
+}
+
+// The game loop keeps serving the table while a bot thinks
+func TestBotThinksOffTheGameLoop(t *testing.T) {
+	session := NewGameSession("bots", 2, 1)
+	bot := slowBot{release: make(chan struct{})}
+	if err := session.AddBot(1, "Slow", "slow", bot); err != nil {
+		t.Fatal(err)
+	}
+	go session.RunGameLoop()
+
+	time.Sleep(300 * time.Millisecond) // The bot starts thinking on the first tick
+	start := time.Now()
+	if _, err := session.submitAction(2, game.Action{Type: game.Rest}); !errors.Is(err, errNotYourTurn) {
+		t.Fatalf("got %v, want %v", err, errNotYourTurn)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("the game loop took %v to answer while the bot thought", waited)
+	}
+
+	close(bot.release)
+	deadline := time.Now().Add(2 * time.Second)
+	for currentSeat(session) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("the bot's move was never played")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// A move chosen for a turn that has passed is dropped
+func TestStaleBotMoveDropped(t *testing.T) {
+	session := NewGameSession("stale", 2, 1)
+	session.Bots[1] = slowBot{}
+	session.botThinking = true
+	// The bot's seat is up again a round later
+	session.GameState.NextTurn()
+	session.GameState.NextTurn()
+	turn := session.GameState.CurrentTurn
+
+	session.playBotAction(PlayerAction{PlayerID: 1, Action: game.Action{Type: game.Rest}, bot: true, turn: turn - 2})
+	if session.botThinking {
+		t.Error("still thinking after the bot's move came back")
+	}
+	if session.GameState.CurrentTurn != turn || currentSeat(session) != 1 {
+		t.Errorf("a stale bot move was played")
+	}
+}
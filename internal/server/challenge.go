@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+
+	"golem_century/internal/challenge"
+)
+
+// CreateChallengeSession creates a one-seat session playing a solo challenge.
+// Like CreateSession, it fails when the ID is taken or the server is full.
+func (gs *GameServer) CreateChallengeSession(sessionID string, c *challenge.Challenge) (*GameSession, error) {
+	gameState, err := c.NewGame()
+	if err != nil {
+		return nil, err
+	}
+
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	session := newGameSession(sessionID, gameState)
+	session.Challenge = c
+	return gs.addSession(session, false)
+}
+
+// challengeInfo describes the session's challenge and how the attempt stands,
+// or nil outside challenge games (caller must hold gs.mu)
+func (gs *GameSession) challengeInfo() map[string]interface{} {
+	if gs.Challenge == nil {
+		return nil
+	}
+	result := gs.Challenge.Check(gs.GameState)
+	if !gs.GameState.GameOver {
+		// Check counts the current turn as taken, but it is still to be played
+		turns := gs.GameState.CurrentTurn
+		result = challenge.Result{Status: challenge.Playing, Turns: turns, TurnsLeft: gs.Challenge.Goal.MaxTurns - turns}
+	}
+	return map[string]interface{}{
+		"id":          gs.Challenge.ID,
+		"name":        gs.Challenge.Name,
+		"description": gs.Challenge.Description,
+		"goal":        gs.Challenge.GoalText(),
+		"rubric":      gs.Challenge.Rubric,
+		"result":      result,
+	}
+}
+
+// HandleListChallenges lists the built-in solo challenges
+func (gs *GameServer) HandleListChallenges(w http.ResponseWriter, r *http.Request) {
+	challenges := make([]map[string]interface{}, 0)
+	for _, c := range challenge.Builtin() {
+		challenges = append(challenges, map[string]interface{}{
+			"id":          c.ID,
+			"name":        c.Name,
+			"description": c.Description,
+			"goal":        c.GoalText(),
+			"rubric":      c.Rubric,
+		})
+	}
+	writeJSON(w, map[string]interface{}{"challenges": challenges})
+}
@@ -0,0 +1,258 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxChatLength    = 200             // Maximum characters per chat message
+	chatHistorySize  = 50              // Messages kept per channel and replayed on join
+	chatBurst        = 5               // Messages allowed in a burst
+	chatRefillPeriod = 2 * time.Second // One more message allowed every period
+	spectatorChannel = "spectators"    // Channel for spectator-only chat
+	playerChannel    = "players"       // Channel players talk on (spectators can read it)
+)
+
+// Emotes lists the emotes players can send
+var Emotes = map[string]bool{
+	"wave":     true,
+	"gg":       true,
+	"thumbsup": true,
+	"think":    true,
+	"laugh":    true,
+	"wow":      true,
+	"sorry":    true,
+}
+
+// ChatFilter inspects a chat message before it is delivered.
+// It returns the (possibly masked) text, or an error to reject the message.
+type ChatFilter func(text string) (string, error)
+
+// NewWordFilter returns a ChatFilter that masks the given words (case-insensitive)
+func NewWordFilter(words []string) ChatFilter {
+	blocked := make([]string, 0, len(words))
+	for _, w := range words {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if w != "" {
+			blocked = append(blocked, w)
+		}
+	}
+	return func(text string) (string, error) {
+		lower := strings.ToLower(text)
+		masked := []rune(text)
+		for _, word := range blocked {
+			for start := 0; ; {
+				i := strings.Index(lower[start:], word)
+				if i < 0 {
+					break
+				}
+				from := utf8.RuneCountInString(lower[:start+i])
+				for j := 0; j < utf8.RuneCountInString(word); j++ {
+					masked[from+j] = '*'
+				}
+				start += i + len(word)
+			}
+		}
+		return string(masked), nil
+	}
+}
+
+// ChatMessage is a chat line or emote
+type ChatMessage struct {
+	Type     string    `json:"type"` // "chat" or "emote"
+	Channel  string    `json:"channel"`
+	PlayerID int       `json:"playerID,omitempty"` // 0 for spectators
+	Name     string    `json:"name"`
+	Text     string    `json:"text,omitempty"`
+	Emote    string    `json:"emote,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// chatSender identifies who is chatting and holds their rate limit
+type chatSender struct {
+	playerID  int // 0 for spectators
+	name      string
+	spectator bool
+	limiter   *tokenBucket
+}
+
+func newChatSender(playerID int, name string, spectator bool) *chatSender {
+	return &chatSender{
+		playerID:  playerID,
+		name:      name,
+		spectator: spectator,
+		limiter:   newTokenBucket(chatBurst, chatRefillPeriod),
+	}
+}
+
+// handleChatMessage validates and delivers a "chat" or "emote" message
+func (gs *GameServer) handleChatMessage(session *GameSession, sender *chatSender, msgType string, msg map[string]interface{}) error {
+	chat := ChatMessage{
+		Type:     msgType,
+		Channel:  playerChannel,
+		PlayerID: sender.playerID,
+		Name:     sender.name,
+		Time:     time.Now(),
+	}
+	if sender.spectator {
+		chat.Channel = spectatorChannel
+	}
+
+	switch msgType {
+	case "chat":
+		text, _ := msg["text"].(string)
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return fmt.Errorf("empty chat message")
+		}
+		if utf8.RuneCountInString(text) > maxChatLength {
+			return fmt.Errorf("chat message too long (max %d characters)", maxChatLength)
+		}
+		if gs.ChatFilter != nil {
+			filtered, err := gs.ChatFilter(text)
+			if err != nil {
+				return err
+			}
+			text = filtered
+		}
+		chat.Text = text
+	case "emote":
+		emote, _ := msg["emote"].(string)
+		if !Emotes[emote] {
+			return fmt.Errorf("unknown emote %q", emote)
+		}
+		chat.Emote = emote
+	}
+
+	if !sender.limiter.Allow() {
+		return fmt.Errorf("you are sending messages too fast")
+	}
+
+	data, err := json.Marshal(chat)
+	if err != nil {
+		return err
+	}
+
+	session.mu.Lock()
+	if chat.Channel == spectatorChannel {
+		session.SpectatorChat = appendChat(session.SpectatorChat, chat)
+	} else {
+		session.PlayerChat = appendChat(session.PlayerChat, chat)
+	}
+	session.mu.Unlock()
+
+	if chat.Channel == spectatorChannel {
+		session.BroadcastSpectators(data)
+	} else {
+		session.Broadcast(data)
+	}
+	return nil
+}
+
+// appendChat appends a message, keeping only the most recent chatHistorySize
+func appendChat(history []ChatMessage, chat ChatMessage) []ChatMessage {
+	history = append(history, chat)
+	if len(history) > chatHistorySize {
+		history = history[len(history)-chatHistorySize:]
+	}
+	return history
+}
+
+// sendChatHistory sends the recent chat to a newly joined connection.
+// Spectators also get the spectator channel; players never see it.
+func (gs *GameSession) sendChatHistory(conn *wsConn, spectator bool) {
+	gs.mu.RLock()
+	history := map[string]interface{}{
+		"type":    "chatHistory",
+		"players": append([]ChatMessage{}, gs.PlayerChat...),
+	}
+	if spectator {
+		history["spectators"] = append([]ChatMessage{}, gs.SpectatorChat...)
+	}
+	gs.mu.RUnlock()
+
+	if data, err := json.Marshal(history); err == nil {
+		conn.send(data)
+	}
+}
+
+// AddSpectator adds a read-only connection to the session
+func (gs *GameSession) AddSpectator(conn *wsConn, name string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.Spectators[conn] = name
+	gs.LastActivity = time.Now()
+}
+
+// RemoveSpectator removes a spectator connection
+func (gs *GameSession) RemoveSpectator(conn *wsConn) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	delete(gs.Spectators, conn)
+}
+
+// BroadcastSpectators sends a message to spectators only
+func (gs *GameSession) BroadcastSpectators(message []byte) {
+	gs.mu.RLock()
+	conns := make([]*wsConn, 0, len(gs.Spectators))
+	for conn := range gs.Spectators {
+		conns = append(conns, conn)
+	}
+	gs.mu.RUnlock()
+
+	for _, conn := range conns {
+		conn.send(message)
+	}
+}
+
+// handleSpectator runs a spectator connection: it receives state and chat,
+// and may only talk on the spectator channel
+func (gs *GameServer) handleSpectator(w http.ResponseWriter, r *http.Request, session *GameSession) {
+	conn, err := gs.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxMessageSize)
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = "Spectator"
+	}
+	spectator := newWSConn(conn)
+	session.AddSpectator(spectator, name)
+	defer session.RemoveSpectator(spectator)
+
+	if data, err := json.Marshal(session.SerializeState()); err == nil {
+		spectator.send(data)
+	}
+	session.sendChatHistory(spectator, true)
+
+	sender := newChatSender(0, name, true)
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(message, &msg); err != nil {
+			continue
+		}
+		msgType, _ := msg["type"].(string)
+		if msgType != "chat" && msgType != "emote" {
+			continue
+		}
+		if err := gs.handleChatMessage(session, sender, msgType, msg); err != nil {
+			if data, err := json.Marshal(map[string]interface{}{"type": "error", "error": err.Error()}); err == nil {
+				spectator.send(data)
+			}
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golem_century/internal/game"
+)
+
+// Correspondence limits
+const (
+	DefaultTurnDeadline = 24 * time.Hour
+	minTurnDeadline     = time.Hour
+	maxTurnDeadline     = 14 * 24 * time.Hour
+	restActionTimeout   = 5 * time.Second // How long a REST move waits for the game loop
+	webhookTimeout      = 10 * time.Second
+)
+
+// startTurnClock starts the current seat's deadline and notifies that seat.
+// Only correspondence sessions have deadlines.
+func (gs *GameSession) startTurnClock() {
+	gs.mu.Lock()
+	if !gs.Correspondence || gs.GameState.GameOver {
+		gs.mu.Unlock()
+		return
+	}
+	gs.Deadline = time.Now().Add(gs.TurnDeadline)
+	playerID := gs.GameState.GetCurrentPlayer().ID
+	onTurn := gs.onTurn
+	gs.mu.Unlock()
+
+	if onTurn != nil {
+		onTurn(gs, playerID)
+	}
+}
+
+// checkDeadline makes the current seat rest when its deadline has passed (called from the game loop).
+// Seats nobody has taken yet wait without a clock, and a REST claim that never moved is released.
+func (gs *GameSession) checkDeadline() {
+	if gs.GameState.GameOver {
+		return
+	}
+	player := gs.GameState.GetCurrentPlayer()
+	gs.mu.Lock()
+	_, connected := gs.Connections[player.ID]
+	held := connected || gs.LockedSeats[player.ID]
+	expired := gs.Correspondence && held && !gs.Deadline.IsZero() && time.Now().After(gs.Deadline)
+	if expired && !connected && gs.seatClaims[player.ID] {
+		log.Printf("Seat %d in %s was claimed but never played, releasing it", player.ID, gs.ID)
+		gs.releaseClaim(player.ID)
+	}
+	gs.mu.Unlock()
+	if !expired {
+		return
+	}
+	log.Printf("Seat %d in %s missed its deadline, resting", player.ID, gs.ID)
+	if _, err := gs.applyAction(game.Action{Type: game.Rest}); err != nil {
+		log.Printf("Could not rest seat %d in %s: %v", player.ID, gs.ID, err)
+	}
+}
+
+// reserveSeat keeps a seat for its token holder while they are offline
+func (gs *GameSession) reserveSeat(playerID int) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	gs.LockedSeats[playerID] = true
+}
+
+// notifyTurn tells an offline seat that it is their turn, by webhook or email (outbox).
+// Seats without a target fall back to their account's email.
+func (gs *GameServer) notifyTurn(session *GameSession, playerID int) {
+	session.mu.RLock()
+	_, connected := session.Connections[playerID]
+	target := session.NotifyTargets[playerID]
+	userID := session.PlayerUsers[playerID]
+	deadline := session.Deadline
+	session.mu.RUnlock()
+	if connected {
+		return
+	}
+	if target == "" && userID != "" && gs.Accounts != nil {
+		if user, err := gs.Accounts.Get(userID); err == nil {
+			target = user.Email
+		}
+	}
+	if target == "" {
+		return
+	}
+
+	if isWebhook(target) {
+		if !gs.AllowWebhooks {
+			return
+		}
+		payload := map[string]interface{}{
+			"type":      "yourTurn",
+			"sessionID": session.ID,
+			"playerID":  playerID,
+			"deadline":  deadline,
+		}
+		go gs.postWebhook(target, payload)
+		return
+	}
+
+	if gs.Outbox == nil {
+		return
+	}
+	body := fmt.Sprintf("It's your turn in game %s (seat %d).\n\nPlease move before %s or your seat will rest automatically.\n",
+		session.ID, playerID, deadline.Format(time.RFC1123))
+	if _, err := gs.Outbox.Send(target, "Your turn in Century: Golem Edition", body); err != nil {
+		log.Printf("Failed to notify seat %d in %s: %v", playerID, session.ID, err)
+	}
+}
+
+// postWebhook delivers a JSON notification; failures are only logged
+func (gs *GameServer) postWebhook(target string, payload map[string]interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		log.Printf("Invalid webhook %s: %v", target, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Printf("Webhook %s failed: %v", target, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("Webhook %s returned %s", target, resp.Status)
+	}
+}
+
+// isWebhook reports whether a notification target is an http(s) URL
+func isWebhook(target string) bool {
+	return strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")
+}
+
+// validNotifyTarget accepts an email address or an http(s) URL
+func validNotifyTarget(target string) bool {
+	if isWebhook(target) {
+		u, err := url.Parse(target)
+		return err == nil && u.Host != ""
+	}
+	return strings.Contains(target, "@") && !strings.ContainsAny(target, " \r\n")
+}
+
+// HandleTakeSeat takes an open seat of a correspondence game without a WebSocket.
+// It follows the lobby's rules: private games need the passcode, and reserved seats,
+// finished games and kicked accounts are refused. The seat is reserved and the
+// response carries its seat token; a seat that misses its first turn is given up.
+func (gs *GameServer) HandleTakeSeat(w http.ResponseWriter, r *http.Request) {
+	session, ok := gs.sessionFromPath(w, r)
+	if !ok {
+		return
+	}
+	if gs.createLimiter != nil && !gs.createLimiter.Allow(clientIP(r)) {
+		sendRateLimited(w, gs.createLimiter.interval)
+		return
+	}
+	session.mu.RLock()
+	correspondence, gameOver := session.Correspondence, session.GameState.GameOver
+	session.mu.RUnlock()
+	if !correspondence {
+		sendJSONError(w, http.StatusConflict, "Only correspondence games take seats without a connection")
+		return
+	}
+	if gameOver {
+		sendJSONError(w, http.StatusConflict, "Game is over")
+		return
+	}
+
+	var req struct {
+		Name     string `json:"name"`
+		Passcode string `json:"passcode"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		sendJSONError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if utf8.RuneCountInString(req.Name) > maxNameLength {
+		sendJSONError(w, http.StatusBadRequest, fmt.Sprintf("Name too long (max %d characters)", maxNameLength))
+		return
+	}
+	if !session.CheckPasscode(req.Passcode) {
+		sendJSONError(w, http.StatusForbidden, "Invalid passcode")
+		return
+	}
+	userID := gs.userFromRequest(r)
+	if session.IsBanned("", userID) {
+		sendJSONError(w, http.StatusForbidden, "You were removed from this game")
+		return
+	}
+	if session.Rated && userID == "" {
+		sendJSONError(w, http.StatusUnauthorized, "Rated games require signing in")
+		return
+	}
+
+	session.mu.Lock()
+	playerID := 0
+	for i := 1; i <= len(session.GameState.Players); i++ {
+		if _, connected := session.Connections[i]; !connected && !session.LockedSeats[i] {
+			playerID = i
+			break
+		}
+	}
+	if playerID == 0 {
+		session.mu.Unlock()
+		sendJSONError(w, http.StatusForbidden, "Game is full")
+		return
+	}
+	name := req.Name
+	if name == "" {
+		name = fmt.Sprintf("Player %d", playerID)
+	}
+	session.LockedSeats[playerID] = true
+	session.seatClaims[playerID] = true
+	session.PlayerNames[playerID] = name
+	session.GameState.Players[playerID-1].Name = name
+	if userID != "" {
+		session.PlayerUsers[playerID] = userID
+	}
+	token := session.SeatTokens[playerID]
+	session.mu.Unlock()
+
+	session.BroadcastState()
+	writeJSON(w, map[string]interface{}{
+		"sessionID": session.ID,
+		"playerID":  playerID,
+		"seatToken": token,
+	})
+}
+
+// releaseClaim gives up a seat taken over REST whose holder never moved, so a
+// claim cannot hold a seat for good (caller must hold gs.mu)
+func (gs *GameSession) releaseClaim(playerID int) {
+	delete(gs.seatClaims, playerID)
+	delete(gs.LockedSeats, playerID)
+	delete(gs.PlayerNames, playerID)
+	delete(gs.PlayerUsers, playerID)
+	delete(gs.NotifyTargets, playerID)
+	gs.SeatTokens[playerID] = newToken()
+	gs.GameState.Players[playerID-1].Name = fmt.Sprintf("Player %d", playerID)
+}
+
+// HandleSetNotify sets where a seat's turn notifications go (email or webhook URL, "" to stop)
+func (gs *GameServer) HandleSetNotify(w http.ResponseWriter, r *http.Request) {
+	session, ok := gs.sessionFromPath(w, r)
+	if !ok {
+		return
+	}
+	playerID := seatFromRequest(session, r)
+	if playerID == 0 {
+		sendJSONError(w, http.StatusUnauthorized, "Invalid seat token")
+		return
+	}
+
+	var req struct {
+		Target string `json:"target"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	req.Target = strings.TrimSpace(req.Target)
+	if req.Target != "" && !validNotifyTarget(req.Target) {
+		sendJSONError(w, http.StatusBadRequest, "Target must be an email address or http(s) URL")
+		return
+	}
+	if isWebhook(req.Target) && !gs.AllowWebhooks {
+		sendJSONError(w, http.StatusBadRequest, "Webhooks are not enabled on this server")
+		return
+	}
+
+	session.mu.Lock()
+	if req.Target == "" {
+		delete(session.NotifyTargets, playerID)
+	} else {
+		session.NotifyTargets[playerID] = req.Target
+	}
+	session.mu.Unlock()
+	writeJSON(w, map[string]interface{}{"status": "ok", "playerID": playerID, "target": req.Target})
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golem_century/internal/game"
+)
+
+// takeSeat claims a seat over REST and returns the status and reply
+func takeSeat(t *testing.T, url, body string, cookies ...*http.Cookie) (int, map[string]interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var reply map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&reply)
+	return resp.StatusCode, reply
+}
+
+// newCorrespondence creates a correspondence session
+func newCorrespondence(t *testing.T, gs *GameServer, id string, seats int) *GameSession {
+	session, err := gs.CreateSession(id, seats, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session.mu.Lock()
+	session.Correspondence = true
+	session.KeepAlive = true
+	session.TurnDeadline = DefaultTurnDeadline
+	session.mu.Unlock()
+	return session
+}
+
+func TestTakeSeatRules(t *testing.T) {
+	gs := newAccountServer(t)
+	gs.SetCreateRateLimit(0)
+	srv := newTestServer(t, gs)
+
+	if _, err := gs.CreateSession("live", 2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := takeSeat(t, srv.URL+"/api/games/live/seats", `{}`); status != http.StatusConflict {
+		t.Errorf("live game: got %d, want %d", status, http.StatusConflict)
+	}
+
+	session := newCorrespondence(t, gs, "post", 3)
+	session.Private, session.Passcode = true, "pw"
+	session.LockedSeats[2] = true // Reserved by the host
+	url := srv.URL + "/api/games/post/seats"
+	if status, _ := takeSeat(t, url, `{"name":"Ann"}`); status != http.StatusForbidden {
+		t.Errorf("private game without the passcode: got %d, want %d", status, http.StatusForbidden)
+	}
+	status, reply := takeSeat(t, url, `{"name":"Ann","passcode":"pw"}`)
+	if status != http.StatusOK || reply["playerID"] != 1.0 || reply["seatToken"] != session.SeatToken(1) {
+		t.Fatalf("first claim: got %d %v", status, reply)
+	}
+
+	// A kicked account is refused
+	user, err := gs.Accounts.Register("bob", "bob@example.com", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookie := &http.Cookie{Name: sessionCookieName, Value: gs.Signer.Sign(user.ID, time.Now().Add(time.Hour))}
+	session.mu.Lock()
+	session.Banned[user.ID] = true
+	session.mu.Unlock()
+	if status, _ := takeSeat(t, url, `{"passcode":"pw"}`, cookie); status != http.StatusForbidden {
+		t.Errorf("kicked account: got %d, want %d", status, http.StatusForbidden)
+	}
+
+	// Seat 2 is reserved, so the next claim gets seat 3 and then the game is full
+	if status, reply := takeSeat(t, url, `{"passcode":"pw"}`); status != http.StatusOK || reply["playerID"] != 3.0 {
+		t.Errorf("second claim: got %d %v, want seat 3", status, reply)
+	}
+	if status, _ := takeSeat(t, url, `{"passcode":"pw"}`); status != http.StatusForbidden {
+		t.Errorf("full game: got %d, want %d", status, http.StatusForbidden)
+	}
+}
+
+func TestTakeSeatRateLimited(t *testing.T) {
+	gs := NewGameServer()
+	srv := newTestServer(t, gs)
+	for i := 0; i < createBurst; i++ {
+		id := fmt.Sprintf("post%d", i)
+		newCorrespondence(t, gs, id, 2)
+		if status, _ := takeSeat(t, srv.URL+"/api/games/"+id+"/seats", `{}`); status != http.StatusOK {
+			t.Fatalf("claim %d: got %d", i+1, status)
+		}
+	}
+	newCorrespondence(t, gs, "postz", 2)
+	if status, _ := takeSeat(t, srv.URL+"/api/games/postz/seats", `{}`); status != http.StatusTooManyRequests {
+		t.Errorf("claim %d: got %d, want %d", createBurst+1, status, http.StatusTooManyRequests)
+	}
+}
+
+// A seat claimed over REST that misses its first turn is given up
+func TestUnplayedClaimReleased(t *testing.T) {
+	session := NewGameSession("claim", 2, 1)
+	session.Correspondence = true
+	session.LockedSeats[1] = true
+	session.seatClaims[1] = true
+	session.PlayerNames[1] = "Squatter"
+	token := session.SeatTokens[1]
+	session.Deadline = time.Now().Add(-time.Second)
+
+	session.checkDeadline()
+	if session.LockedSeats[1] || session.SeatForToken(token) != 0 || session.PlayerNames[1] != "" {
+		t.Error("the unplayed claim still holds seat 1")
+	}
+	if currentSeat(session) != 2 {
+		t.Error("seat 1 did not rest on its deadline")
+	}
+
+	// Once a seat moves, its claim no longer lapses
+	session.LockedSeats[2] = true
+	session.seatClaims[2] = true
+	if _, err := session.applyAction(game.Action{Type: game.Rest}); err != nil {
+		t.Fatal(err)
+	}
+	if session.seatClaims[2] {
+		t.Error("moving did not settle seat 2's claim")
+	}
+}
@@ -2,15 +2,17 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"regexp"
 	"strconv"
 	"time"
+	"unicode/utf8"
 
+	"golem_century/internal/challenge"
 	"golem_century/internal/game"
-
-	"github.com/gorilla/websocket"
 )
 
 // sendJSONError sends a JSON error response
@@ -23,6 +25,15 @@ func sendJSONError(w http.ResponseWriter, statusCode int, message string) {
 	})
 }
 
+// sessionIDPattern restricts session IDs to short URL-safe strings
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// sendRateLimited sends a 429 response asking the client to retry later
+func sendRateLimited(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	sendJSONError(w, http.StatusTooManyRequests, "Too many requests, please slow down")
+}
+
 // HandleWebSocket handles WebSocket connections
 func (gs *GameServer) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.URL.Query().Get("session")
@@ -33,6 +44,14 @@ func (gs *GameServer) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		sendJSONError(w, http.StatusBadRequest, "Missing session ID")
 		return
 	}
+	if !sessionIDPattern.MatchString(sessionID) {
+		sendJSONError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+	if utf8.RuneCountInString(playerName) > maxNameLength {
+		sendJSONError(w, http.StatusBadRequest, fmt.Sprintf("Name too long (max %d characters)", maxNameLength))
+		return
+	}
 
 	session, ok := gs.GetSession(sessionID)
 	if !ok {
@@ -40,28 +59,64 @@ func (gs *GameServer) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A seat token reclaims (or takes a reserved) seat; private rooms otherwise need the passcode
+	seatToken := r.URL.Query().Get("token")
+	hostToken := r.URL.Query().Get("hostToken")
+	if hostToken == "" {
+		if cookie, err := r.Cookie(hostCookiePrefix + sessionID); err == nil {
+			hostToken = cookie.Value
+		}
+	}
+	tokenSeat := session.SeatForToken(seatToken)
+	if tokenSeat == 0 && hostToken != session.HostToken && !session.CheckPasscode(r.URL.Query().Get("passcode")) {
+		sendJSONError(w, http.StatusForbidden, "Invalid passcode")
+		return
+	}
+
+	if r.URL.Query().Get("spectate") != "" {
+		gs.handleSpectator(w, r, session)
+		return
+	}
+
+	// The host can always come back
+	userID := gs.userFromRequest(r)
+	if hostToken != session.HostToken && session.IsBanned(seatToken, userID) {
+		sendJSONError(w, http.StatusForbidden, "You were removed from this game")
+		return
+	}
+
 	// Auto-assign player ID if not provided or if slot is taken
 	var playerID int
-	if playerIDStr != "" {
+	if tokenSeat != 0 {
+		session.mu.RLock()
+		_, taken := session.Connections[tokenSeat]
+		session.mu.RUnlock()
+		if taken {
+			sendJSONError(w, http.StatusConflict, "Seat already connected")
+			return
+		}
+		playerID = tokenSeat
+	} else if playerIDStr != "" {
 		if _, err := fmt.Sscanf(playerIDStr, "%d", &playerID); err != nil {
 			sendJSONError(w, http.StatusBadRequest, "Invalid player ID")
 			return
 		}
-		// Check if this player ID is already taken
+		// Check if this player ID is already taken or reserved
 		session.mu.RLock()
 		_, taken := session.Connections[playerID]
+		locked := session.LockedSeats[playerID]
 		session.mu.RUnlock()
-		if taken {
+		if taken || locked {
 			playerID = 0 // Force auto-assign
 		}
 	}
 
-	// Auto-assign next available player ID
+	// Auto-assign next available player ID (reserved seats are skipped)
 	if playerID == 0 {
 		session.mu.RLock()
 		maxPlayers := len(session.GameState.Players)
 		for i := 1; i <= maxPlayers; i++ {
-			if _, exists := session.Connections[i]; !exists {
+			if _, exists := session.Connections[i]; !exists && !session.LockedSeats[i] {
 				playerID = i
 				break
 			}
@@ -80,34 +135,56 @@ func (gs *GameServer) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	conn, err := upgrader.Upgrade(w, r, nil)
+	if session.Rated && userID == "" {
+		sendJSONError(w, http.StatusUnauthorized, "Rated games require signing in")
+		return
+	}
+
+	conn, err := gs.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
 		return
 	}
 	defer conn.Close()
+	conn.SetReadLimit(maxMessageSize)
 
-	// Add player to session
+	// Add player to session; signed-in players default to their username
+	if playerName == "" && userID != "" {
+		if user, err := gs.Accounts.Get(userID); err == nil {
+			playerName = user.Username
+		}
+	}
 	if playerName == "" {
 		playerName = fmt.Sprintf("Player %d", playerID)
 	}
 	playerAvatar := r.URL.Query().Get("avatar")
-	session.AddPlayer(playerID, playerName, playerAvatar, conn)
+	client := newWSConn(conn)
+	client.userID = userID
+	session.AddPlayer(playerID, playerName, playerAvatar, client)
+	session.SetSeatUser(playerID, userID)
+	session.ClaimHost(playerID, hostToken)
+	if session.Correspondence {
+		// Correspondence players come and go; keep the seat for their token
+		session.reserveSeat(playerID)
+	}
 
-	// Send assigned player ID back to client
+	// Send assigned player ID back to client, with the token to reclaim the seat
 	assignedMsg := map[string]interface{}{
-		"type":     "playerAssigned",
-		"playerID": playerID,
+		"type":      "playerAssigned",
+		"playerID":  playerID,
+		"seatToken": session.SeatToken(playerID),
+		"isHost":    session.IsHost(playerID),
+		"userID":    userID,
 	}
 	if data, err := json.Marshal(assignedMsg); err == nil {
-		conn.WriteMessage(websocket.TextMessage, data)
+		client.send(data)
 	}
 
-	// Send initial state
-	state := session.SerializeState()
-	if data, err := json.Marshal(state); err == nil {
-		conn.WriteMessage(websocket.TextMessage, data)
-	}
+	// Everyone else needs the updated seat/host info; the new player gets it too
+	session.BroadcastState()
+	session.sendChatHistory(client, false)
+	chatSender := newChatSender(playerID, playerName, false)
+	actionLimiter := newTokenBucket(actionBurst, actionRefillPeriod)
 
 	// Handle incoming messages
 	for {
@@ -127,162 +204,262 @@ func (gs *GameServer) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		if !ok {
 			continue
 		}
+		// Chat has its own limit; everything else shares the action limit
+		if actionType != "chat" && actionType != "emote" && !actionLimiter.Allow() {
+			sendWSError(session, playerID, fmt.Errorf("rate limited: too many actions, slow down"))
+			continue
+		}
 
 		switch actionType {
 		case "action":
-			actionTypeStr, _ := actionMsg["actionType"].(string)
-			cardIndex, _ := actionMsg["cardIndex"].(float64)
-
-			// Parse input and output resources if present
-			var inputResources *game.Resources
-			var outputResources *game.Resources
-
-			if inputRes, ok := actionMsg["inputResources"].(map[string]interface{}); ok {
-				getInt := func(m map[string]interface{}, key string) int {
-					if val, exists := m[key]; exists {
-						if f, ok := val.(float64); ok {
-							return int(f)
-						}
-					}
-					return 0
-				}
-				inputResources = &game.Resources{
-					Yellow: getInt(inputRes, "yellow"),
-					Green:  getInt(inputRes, "green"),
-					Blue:   getInt(inputRes, "blue"),
-					Pink:   getInt(inputRes, "pink"),
-				}
+			gameAction, err := parseAction(actionMsg)
+			if err != nil {
+				sendWSError(session, playerID, err)
+				continue
 			}
 
-			if outputRes, ok := actionMsg["outputResources"].(map[string]interface{}); ok {
-				getInt := func(m map[string]interface{}, key string) int {
-					if val, exists := m[key]; exists {
-						if f, ok := val.(float64); ok {
-							return int(f)
-						}
-					}
-					return 0
-				}
-				outputResources = &game.Resources{
-					Yellow: getInt(outputRes, "yellow"),
-					Green:  getInt(outputRes, "green"),
-					Blue:   getInt(outputRes, "blue"),
-					Pink:   getInt(outputRes, "pink"),
-				}
+			session.ActionChan <- PlayerAction{
+				PlayerID: playerID,
+				Action:   gameAction,
 			}
 
-			// Parse multiplier if present
-			multiplier := 1
-			if mult, ok := actionMsg["multiplier"].(float64); ok {
-				multiplier = int(mult)
-				if multiplier < 1 {
-					multiplier = 1
-				}
+		case "chat", "emote":
+			if err := gs.handleChatMessage(session, chatSender, actionType, actionMsg); err != nil {
+				sendWSError(session, playerID, err)
 			}
 
-			var gameAction game.Action
-			switch actionTypeStr {
-			case "playCard":
-				gameAction = game.Action{
-					Type:            game.PlayCard,
-					CardIndex:       int(cardIndex),
-					Multiplier:      multiplier,
-					InputResources:  inputResources,
-					OutputResources: outputResources,
-				}
-			case "acquireCard":
-				gameAction = game.Action{
-					Type:      game.AcquireCard,
-					CardIndex: int(cardIndex),
-				}
-			case "claimPointCard":
-				gameAction = game.Action{
-					Type:      game.ClaimPointCard,
-					CardIndex: int(cardIndex),
-				}
-			case "rest":
-				gameAction = game.Action{
-					Type: game.Rest,
-				}
-			case "discardCrystals":
-				discardMap, _ := actionMsg["discard"].(map[string]interface{})
-				getInt := func(m map[string]interface{}, key string) int {
-					if val, exists := m[key]; exists {
-						if f, ok := val.(float64); ok {
-							return int(f)
-						}
-					}
-					return 0
-				}
-				discard := &game.Resources{
-					Yellow: getInt(discardMap, "yellow"),
-					Green:  getInt(discardMap, "green"),
-					Blue:   getInt(discardMap, "blue"),
-					Pink:   getInt(discardMap, "pink"),
-				}
-				gameAction = game.Action{
-					Type:    game.DiscardCrystals,
-					Discard: discard,
-				}
-			case "depositCrystals":
-				depositsMap, _ := actionMsg["deposits"].(map[string]interface{})
-				targetPos, _ := actionMsg["targetPosition"].(float64)
-				deposits := make(map[int][]game.CrystalType)
-				for posStr, crystalStr := range depositsMap {
-					pos, _ := strconv.Atoi(posStr)
-					crystalName, _ := crystalStr.(string)
-					var crystalType game.CrystalType
-					switch crystalName {
-					case "yellow":
-						crystalType = game.Yellow
-					case "green":
-						crystalType = game.Green
-					case "blue":
-						crystalType = game.Blue
-					case "pink":
-						crystalType = game.Pink
-					default:
-						continue
-					}
-					// Wrap single crystal in array to support stacking
-					deposits[pos] = []game.CrystalType{crystalType}
-				}
-				gameAction = game.Action{
-					Type:           game.DepositCrystals,
-					CardIndex:      int(cardIndex),
-					Deposits:       deposits,
-					TargetPosition: int(targetPos),
-				}
-			case "collectCrystals":
-				positionsArr, _ := actionMsg["positions"].([]interface{})
-				positions := make([]int, 0, len(positionsArr))
-				for _, pos := range positionsArr {
-					if f, ok := pos.(float64); ok {
-						positions = append(positions, int(f))
-					}
+		case "hint":
+			gs.handleHint(session, playerID)
+
+		case "suggestUpgrade":
+			if err := handleSuggestUpgrade(session, playerID, actionMsg); err != nil {
+				sendWSError(session, playerID, err)
+			}
+
+		case "golemPaths":
+			if err := handleGolemPaths(session, playerID); err != nil {
+				sendWSError(session, playerID, err)
+			}
+
+		case "kick", "lockSeat", "setPrivate", "addBot":
+			if err := gs.handleHostMessage(session, playerID, actionType, actionMsg); err != nil {
+				sendWSError(session, playerID, err)
+				continue
+			}
+			session.BroadcastState()
+		}
+	}
+
+	session.removeConnection(playerID, client)
+	session.BroadcastState()
+}
+
+// parseAction converts an "action" message (WebSocket or REST) into a game action
+func parseAction(actionMsg map[string]interface{}) (game.Action, error) {
+	actionTypeStr, _ := actionMsg["actionType"].(string)
+	cardIndex, _ := actionMsg["cardIndex"].(float64)
+
+	// Parse input and output resources if present
+	var inputResources *game.Resources
+	var outputResources *game.Resources
+
+	if inputRes, ok := actionMsg["inputResources"].(map[string]interface{}); ok {
+		getInt := func(m map[string]interface{}, key string) int {
+			if val, exists := m[key]; exists {
+				if f, ok := val.(float64); ok {
+					return int(f)
 				}
-				gameAction = game.Action{
-					Type:             game.CollectCrystals,
-					CardIndex:        int(cardIndex),
-					CollectPositions: positions,
+			}
+			return 0
+		}
+		inputResources = &game.Resources{
+			Yellow: getInt(inputRes, "yellow"),
+			Green:  getInt(inputRes, "green"),
+			Blue:   getInt(inputRes, "blue"),
+			Pink:   getInt(inputRes, "pink"),
+		}
+	}
+
+	if outputRes, ok := actionMsg["outputResources"].(map[string]interface{}); ok {
+		getInt := func(m map[string]interface{}, key string) int {
+			if val, exists := m[key]; exists {
+				if f, ok := val.(float64); ok {
+					return int(f)
 				}
-			case "collectAllCrystals":
-				gameAction = game.Action{
-					Type:      game.CollectAllCrystals,
-					CardIndex: int(cardIndex),
+			}
+			return 0
+		}
+		outputResources = &game.Resources{
+			Yellow: getInt(outputRes, "yellow"),
+			Green:  getInt(outputRes, "green"),
+			Blue:   getInt(outputRes, "blue"),
+			Pink:   getInt(outputRes, "pink"),
+		}
+	}
+
+	// Parse multiplier if present
+	multiplier := 1
+	if mult, ok := actionMsg["multiplier"].(float64); ok {
+		multiplier = int(mult)
+		if multiplier < 1 {
+			multiplier = 1
+		}
+		// No one can hold enough crystals to trade more often than this
+		if multiplier > game.MaxCrystals {
+			multiplier = game.MaxCrystals
+		}
+	}
+
+	var gameAction game.Action
+	switch actionTypeStr {
+	case "playCard":
+		gameAction = game.Action{
+			Type:            game.PlayCard,
+			CardIndex:       int(cardIndex),
+			Multiplier:      multiplier,
+			InputResources:  inputResources,
+			OutputResources: outputResources,
+		}
+	case "acquireCard":
+		gameAction = game.Action{
+			Type:      game.AcquireCard,
+			CardIndex: int(cardIndex),
+		}
+	case "claimPointCard":
+		gameAction = game.Action{
+			Type:      game.ClaimPointCard,
+			CardIndex: int(cardIndex),
+		}
+	case "rest":
+		gameAction = game.Action{
+			Type: game.Rest,
+		}
+	case "discardCrystals":
+		discardMap, _ := actionMsg["discard"].(map[string]interface{})
+		getInt := func(m map[string]interface{}, key string) int {
+			if val, exists := m[key]; exists {
+				if f, ok := val.(float64); ok {
+					return int(f)
 				}
+			}
+			return 0
+		}
+		discard := &game.Resources{
+			Yellow: getInt(discardMap, "yellow"),
+			Green:  getInt(discardMap, "green"),
+			Blue:   getInt(discardMap, "blue"),
+			Pink:   getInt(discardMap, "pink"),
+		}
+		gameAction = game.Action{
+			Type:    game.DiscardCrystals,
+			Discard: discard,
+		}
+	case "depositCrystals":
+		depositsMap, _ := actionMsg["deposits"].(map[string]interface{})
+		targetPos, _ := actionMsg["targetPosition"].(float64)
+		deposits := make(map[int][]game.CrystalType)
+		for posStr, crystalStr := range depositsMap {
+			pos, _ := strconv.Atoi(posStr)
+			crystalName, _ := crystalStr.(string)
+			var crystalType game.CrystalType
+			switch crystalName {
+			case "yellow":
+				crystalType = game.Yellow
+			case "green":
+				crystalType = game.Green
+			case "blue":
+				crystalType = game.Blue
+			case "pink":
+				crystalType = game.Pink
 			default:
 				continue
 			}
-
-			session.ActionChan <- PlayerAction{
-				PlayerID: playerID,
-				Action:   gameAction,
+			// Wrap single crystal in array to support stacking
+			deposits[pos] = []game.CrystalType{crystalType}
+		}
+		gameAction = game.Action{
+			Type:           game.DepositCrystals,
+			CardIndex:      int(cardIndex),
+			Deposits:       deposits,
+			TargetPosition: int(targetPos),
+		}
+	case "collectCrystals":
+		positionsArr, _ := actionMsg["positions"].([]interface{})
+		positions := make([]int, 0, len(positionsArr))
+		for _, pos := range positionsArr {
+			if f, ok := pos.(float64); ok {
+				positions = append(positions, int(f))
 			}
 		}
+		gameAction = game.Action{
+			Type:             game.CollectCrystals,
+			CardIndex:        int(cardIndex),
+			CollectPositions: positions,
+		}
+	case "collectAllCrystals":
+		gameAction = game.Action{
+			Type:      game.CollectAllCrystals,
+			CardIndex: int(cardIndex),
+		}
+	default:
+		return game.Action{}, fmt.Errorf("unknown action type %q", actionTypeStr)
 	}
 
-	session.RemovePlayer(playerID)
+	return gameAction, nil
+}
+
+// handleHostMessage applies a host control message from playerID
+func (gs *GameServer) handleHostMessage(session *GameSession, playerID int, msgType string, msg map[string]interface{}) error {
+	switch msgType {
+	case "kick":
+		target, _ := msg["playerID"].(float64)
+		return session.Kick(playerID, int(target))
+	case "lockSeat":
+		seat, _ := msg["seat"].(float64)
+		locked, _ := msg["locked"].(bool)
+		token, err := session.SetSeatLocked(playerID, int(seat), locked)
+		if err != nil {
+			return err
+		}
+		// Only the host learns the token, so they can invite someone into the reserved seat
+		reply := map[string]interface{}{
+			"type":      "seatToken",
+			"seat":      int(seat),
+			"locked":    locked,
+			"seatToken": token,
+		}
+		if data, err := json.Marshal(reply); err == nil {
+			session.SendToPlayer(playerID, data)
+		}
+		return nil
+	case "addBot":
+		// {"type":"addBot","seat":3,"difficulty":"hard","personality":"rusher","name":"..."}
+		seat, _ := msg["seat"].(float64)
+		difficulty, _ := msg["difficulty"].(string)
+		personality, _ := msg["personality"].(string)
+		name, _ := msg["name"].(string)
+		return gs.hostAddBot(session, playerID, BotSeat{
+			Seat:        int(seat),
+			Name:        name,
+			BotSettings: game.BotSettings{Difficulty: game.Difficulty(difficulty), Personality: game.Personality(personality)},
+		})
+	case "setPrivate":
+		private, _ := msg["private"].(bool)
+		passcode, _ := msg["passcode"].(string)
+		return session.SetPrivate(playerID, private, passcode)
+	}
+	return fmt.Errorf("unknown host message %q", msgType)
+}
+
+// sendWSError sends an error message to a single player
+func sendWSError(session *GameSession, playerID int, err error) {
+	errorMsg := map[string]interface{}{
+		"type":  "error",
+		"error": err.Error(),
+	}
+	if data, err := json.Marshal(errorMsg); err == nil {
+		session.SendToPlayer(playerID, data)
+	}
 }
 
 // HandleCreateSession creates a new game session
@@ -296,18 +473,87 @@ func (gs *GameServer) HandleCreateSession(w http.ResponseWriter, r *http.Request
 		NumPlayers int    `json:"numPlayers"`
 		Seed       int64  `json:"seed"`
 		SessionID  string `json:"sessionID"` // Optional custom session ID
+		Private    bool   `json:"private"`   // Hide from the lobby and require a passcode
+		Passcode   string `json:"passcode"`
+		Rated      bool   `json:"rated"` // Rated games require every player to be signed in
+		// Correspondence games survive without connections; each turn has a deadline
+		Correspondence bool    `json:"correspondence"`
+		TurnHours      float64 `json:"turnHours"` // Default 24
+		// Server-side bots, e.g. [{"seat":2,"difficulty":"expert","personality":"coin-chaser"}]
+		Bots    []BotSeat `json:"bots"`
+		NoHints bool      `json:"noHints"` // Turn off hints (rated games never have them)
+		// Built-in challenge ID; the game is then a solo challenge with one seat
+		Challenge string `json:"challenge"`
+	}
+
+	if gs.createLimiter != nil && !gs.createLimiter.Allow(clientIP(r)) {
+		sendRateLimited(w, gs.createLimiter.interval)
+		return
 	}
 
+	r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		sendJSONError(w, http.StatusBadRequest, "Invalid request")
 		return
 	}
 
-	if req.NumPlayers < 2 || req.NumPlayers > 4 {
+	var solo *challenge.Challenge
+	if req.Challenge != "" {
+		var ok bool
+		if solo, ok = challenge.Get(req.Challenge); !ok {
+			sendJSONError(w, http.StatusBadRequest, "Unknown challenge")
+			return
+		}
+		if req.Rated || len(req.Bots) > 0 {
+			sendJSONError(w, http.StatusBadRequest, "Challenges are solo games: they cannot be rated or have bots")
+			return
+		}
+		req.NumPlayers = 1
+	} else if req.NumPlayers < game.MinPlayers || req.NumPlayers > game.MaxPlayers {
 		sendJSONError(w, http.StatusBadRequest, "Invalid number of players")
 		return
 	}
 
+	if req.Rated && (gs.Ratings == nil || gs.Accounts == nil) {
+		sendJSONError(w, http.StatusBadRequest, "Rated games are not enabled on this server")
+		return
+	}
+
+	if req.Private && req.Passcode == "" {
+		sendJSONError(w, http.StatusBadRequest, "Private rooms need a passcode")
+		return
+	}
+
+	turnDeadline := DefaultTurnDeadline
+	if req.TurnHours != 0 {
+		turnDeadline = time.Duration(req.TurnHours * float64(time.Hour))
+	}
+	if req.Correspondence && (turnDeadline < minTurnDeadline || turnDeadline > maxTurnDeadline) {
+		sendJSONError(w, http.StatusBadRequest, fmt.Sprintf("turnHours must be between %d and %d",
+			int(minTurnDeadline.Hours()), int(maxTurnDeadline.Hours())))
+		return
+	}
+
+	bots := make(map[int]game.Bot, len(req.Bots))
+	botSeats := make(map[int]BotSeat, len(req.Bots))
+	for _, seat := range req.Bots {
+		if seat.Seat < 1 || seat.Seat > req.NumPlayers {
+			sendJSONError(w, http.StatusBadRequest, fmt.Sprintf("Invalid bot seat %d", seat.Seat))
+			return
+		}
+		if _, dup := bots[seat.Seat]; dup {
+			sendJSONError(w, http.StatusBadRequest, fmt.Sprintf("Seat %d has two bots", seat.Seat))
+			return
+		}
+		bot, seat, err := gs.newBot(seat)
+		if err != nil {
+			sendJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		bots[seat.Seat] = bot
+		botSeats[seat.Seat] = seat
+	}
+
 	if req.Seed == 0 {
 		req.Seed = time.Now().UnixNano()
 	}
@@ -315,9 +561,8 @@ func (gs *GameServer) HandleCreateSession(w http.ResponseWriter, r *http.Request
 	// Use custom session ID if provided, otherwise generate one
 	var sessionID string
 	if req.SessionID != "" {
-		// Check if session already exists
-		if _, exists := gs.GetSession(req.SessionID); exists {
-			sendJSONError(w, http.StatusConflict, "Session ID already exists")
+		if !sessionIDPattern.MatchString(req.SessionID) {
+			sendJSONError(w, http.StatusBadRequest, "Invalid session ID: use 1-64 letters, digits, '_' or '-'")
 			return
 		}
 		sessionID = req.SessionID
@@ -325,11 +570,57 @@ func (gs *GameServer) HandleCreateSession(w http.ResponseWriter, r *http.Request
 		sessionID = fmt.Sprintf("session_%d", time.Now().UnixNano())
 	}
 
-	_ = gs.CreateSession(sessionID, req.NumPlayers, req.Seed)
+	var session *GameSession
+	var err error
+	if solo != nil {
+		session, err = gs.CreateChallengeSession(sessionID, solo)
+	} else {
+		session, err = gs.CreateSession(sessionID, req.NumPlayers, req.Seed)
+	}
+	switch {
+	case errors.Is(err, errServerFull):
+		sendJSONError(w, http.StatusServiceUnavailable, "Server is full, too many active sessions")
+		return
+	case errors.Is(err, errSessionExists):
+		sendJSONError(w, http.StatusConflict, "Session ID already exists")
+		return
+	case err != nil:
+		sendJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	session.mu.Lock()
+	session.Private = req.Private
+	session.Passcode = req.Passcode
+	session.Rated = req.Rated
+	session.HintsDisabled = req.NoHints
+	if req.Correspondence {
+		session.Correspondence = true
+		session.KeepAlive = true
+		session.TurnDeadline = turnDeadline
+	}
+	session.mu.Unlock()
+	for seat, bot := range bots {
+		if err := session.AddBot(seat, botSeats[seat].Name, botSeats[seat].strategy(), bot); err != nil {
+			log.Printf("Failed to add bot to %s: %v", sessionID, err)
+		}
+	}
+	session.startTurnClock()
 
+	// The creator connects with hostToken (or the host cookie) to become host
+	http.SetCookie(w, gs.newCookie(hostCookiePrefix+sessionID, session.HostToken, 24*time.Hour))
 	response := map[string]interface{}{
 		"sessionID":  sessionID,
 		"numPlayers": req.NumPlayers,
+		"hostToken":  session.HostToken,
+		"private":    req.Private,
+		"rated":      req.Rated,
+	}
+	if req.Correspondence {
+		response["correspondence"] = true
+		response["turnHours"] = turnDeadline.Hours()
+	}
+	if solo != nil {
+		response["challenge"] = solo.ID
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -343,6 +634,10 @@ func (gs *GameServer) HandleJoinSession(w http.ResponseWriter, r *http.Request)
 		sendJSONError(w, http.StatusBadRequest, "Missing session ID")
 		return
 	}
+	if !sessionIDPattern.MatchString(sessionID) {
+		sendJSONError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
 
 	session, ok := gs.GetSession(sessionID)
 	if !ok {
@@ -350,6 +645,11 @@ func (gs *GameServer) HandleJoinSession(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if session.SeatForToken(r.URL.Query().Get("token")) == 0 && !session.CheckPasscode(r.URL.Query().Get("passcode")) {
+		sendJSONError(w, http.StatusForbidden, "Invalid passcode")
+		return
+	}
+
 	// Return session info
 	response := map[string]interface{}{
 		"sessionID":  sessionID,
@@ -371,7 +671,17 @@ func (gs *GameServer) HandleListSessions(w http.ResponseWriter, r *http.Request)
 		session.mu.RLock()
 		connectedPlayers := len(session.Connections)
 		maxPlayers := len(session.GameState.Players)
-		isFull := connectedPlayers >= maxPlayers
+		reservedSeats := 0
+		for seat := range session.LockedSeats {
+			if _, connected := session.Connections[seat]; !connected {
+				reservedSeats++
+			}
+		}
+		isFull := connectedPlayers+reservedSeats >= maxPlayers
+		isPrivate := session.Private
+		isRated := session.Rated
+		isCorrespondence := session.Correspondence
+		keepAlive := session.KeepAlive
 		isGameOver := session.GameState.GameOver
 
 		// Get player names
@@ -385,20 +695,22 @@ func (gs *GameServer) HandleListSessions(w http.ResponseWriter, r *http.Request)
 		timeSinceActivity := time.Since(session.LastActivity)
 		timeUntilDelete := 5*time.Minute - timeSinceActivity
 		var timeUntilDeleteSeconds int64
-		if timeUntilDelete > 0 && connectedPlayers == 0 {
+		if timeUntilDelete > 0 && connectedPlayers == 0 && !keepAlive {
 			timeUntilDeleteSeconds = int64(timeUntilDelete.Seconds())
 		}
 
 		session.mu.RUnlock()
 
-		// Only show active, non-full, non-game-over sessions
-		if !isFull && !isGameOver {
+		// Only show active, public, non-full, non-game-over sessions
+		if !isFull && !isGameOver && !isPrivate {
 			sessions = append(sessions, map[string]interface{}{
 				"sessionID":        sessionID,
 				"numPlayers":       maxPlayers,
 				"connectedPlayers": connectedPlayers,
 				"players":          playerNames,
 				"status":           "open",
+				"rated":            isRated,
+				"correspondence":   isCorrespondence,
 				"timeUntilDelete":  timeUntilDeleteSeconds, // Seconds until auto-delete (only if empty)
 			})
 		}
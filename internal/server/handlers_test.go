@@ -0,0 +1,75 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"golem_century/internal/game"
+)
+
+func TestParseActionMultiplier(t *testing.T) {
+	tests := []struct {
+		multiplier interface{}
+		want       int
+	}{
+		{nil, 1},
+		{3.0, 3},
+		{0.0, 1},
+		{-5.0, 1},
+		{float64(game.MaxCrystals), game.MaxCrystals},
+		{1e18, game.MaxCrystals}, // Clamped before it can overflow the trade's input
+	}
+	for _, tt := range tests {
+		msg := map[string]interface{}{"actionType": "playCard", "cardIndex": 0.0}
+		if tt.multiplier != nil {
+			msg["multiplier"] = tt.multiplier
+		}
+		action, err := parseAction(msg)
+		if err != nil {
+			t.Fatalf("multiplier %v: %v", tt.multiplier, err)
+		}
+		if action.Multiplier != tt.want {
+			t.Errorf("multiplier %v: got %d, want %d", tt.multiplier, action.Multiplier, tt.want)
+		}
+	}
+}
+
+func TestCreateSessionRateLimited(t *testing.T) {
+	gs := NewGameServer()
+	for i := 0; i < createBurst; i++ {
+		if w := post(gs.HandleCreateSession, "/api/create", `{"numPlayers":2}`); w.Code != http.StatusOK {
+			t.Fatalf("game %d: got %d: %s", i+1, w.Code, w.Body)
+		}
+	}
+	w := post(gs.HandleCreateSession, "/api/create", `{"numPlayers":2}`)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("game %d: got %d with Retry-After %q, want %d", createBurst+1, w.Code, w.Header().Get("Retry-After"), http.StatusTooManyRequests)
+	}
+}
+
+// Game messages past the burst are refused without reaching the game
+func TestActionsRateLimited(t *testing.T) {
+	gs := NewGameServer()
+	srv := newTestServer(t, gs)
+	if _, err := gs.CreateSession("flood", 2, 1); err != nil {
+		t.Fatal(err)
+	}
+	conn, status := dial(t, srv, "/ws?session=flood&name=Ann")
+	if conn == nil {
+		t.Fatalf("connect: got %d", status)
+	}
+	readType(t, conn, "playerAssigned")
+	for i := 0; i <= actionBurst; i++ {
+		if err := conn.WriteJSON(map[string]interface{}{"type": "action", "actionType": "fly"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i <= actionBurst+1; i++ {
+		msg := readType(t, conn, "error")
+		limited := strings.HasPrefix(msg["error"].(string), "rate limited")
+		if limited != (i > actionBurst) {
+			t.Fatalf("message %d: got error %q", i, msg["error"])
+		}
+	}
+}
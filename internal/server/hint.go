@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"golem_century/internal/game"
+)
+
+// Hint settings
+const (
+	hintCount = 3 // Suggestions per hint
+	hintDepth = 2 // Lookahead depth of the hint bot
+)
+
+// hintsAllowed reports whether players may ask for hints (caller must hold gs.mu)
+func (gs *GameSession) hintsAllowed() bool {
+	return !gs.Rated && !gs.HintsDisabled
+}
+
+// viewFor copies the game as playerID sees it, for hints to search while the game
+// loop moves on. The copy is taken under gs.mu, since applyAction changes the game
+// under it; it is nil when hints are disabled.
+func (gs *GameSession) viewFor(playerID int) (view *game.GameState, allowed, gameOver bool) {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	allowed, gameOver = gs.hintsAllowed(), gs.GameState.GameOver
+	if allowed {
+		view = gs.GameState.ViewFor(playerID)
+	}
+	return view, allowed, gameOver
+}
+
+// handleHint answers a player's hint request with the best few actions for their
+// turn, found by a bot that only sees what the player sees
+func (gs *GameServer) handleHint(session *GameSession, playerID int) {
+	view, allowed, gameOver := session.viewFor(playerID)
+
+	switch {
+	case !allowed:
+		sendWSError(session, playerID, fmt.Errorf("hints are disabled in this game"))
+		return
+	case gameOver:
+		sendWSError(session, playerID, fmt.Errorf("the game is over"))
+		return
+	case !gs.hintLimiter.Allow(fmt.Sprintf("%s/%d", session.ID, playerID)):
+		sendWSError(session, playerID, fmt.Errorf("rate limited: one hint every %v", hintRefillPeriod))
+		return
+	}
+
+	hints := view.Hints(playerID, game.NewLookaheadBot(gs.botWeights(), hintDepth), hintCount)
+	reply := map[string]interface{}{
+		"type":  "hints",
+		"hints": hints,
+	}
+	if data, err := json.Marshal(reply); err == nil {
+		session.SendToPlayer(playerID, data)
+	}
+}
+
+// handleSuggestUpgrade works out the best play of an upgrade card in the player's hand,
+// toward a market golem ("golemIndex") or for the most crystal value. It only saves the
+// player the arithmetic of the upgrade dialog, so it works in rated games too.
+func handleSuggestUpgrade(session *GameSession, playerID int, msg map[string]interface{}) error {
+	cardIndex, ok := msg["cardIndex"].(float64)
+	if !ok {
+		return fmt.Errorf("missing cardIndex")
+	}
+
+	session.mu.RLock()
+	player := session.GameState.Players[playerID-1]
+	if int(cardIndex) < 0 || int(cardIndex) >= len(player.Hand) {
+		session.mu.RUnlock()
+		return fmt.Errorf("invalid card index")
+	}
+	card := player.Hand[int(cardIndex)]
+	if card.Type != game.ActionCard || card.ActionType != game.Upgrade {
+		session.mu.RUnlock()
+		return fmt.Errorf("that card is not an upgrade card")
+	}
+	var requirement *game.Resources
+	if golemIndex, ok := msg["golemIndex"].(float64); ok {
+		golems := session.GameState.Market.PointCards
+		if int(golemIndex) < 0 || int(golemIndex) >= len(golems) {
+			session.mu.RUnlock()
+			return fmt.Errorf("invalid golem index")
+		}
+		requirement = golems[int(golemIndex)].Requirement
+	}
+	action, ok := game.PlanUpgrade(player, card, int(cardIndex), requirement)
+	session.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("nothing to upgrade")
+	}
+	reply := map[string]interface{}{
+		"type":            "upgradeSuggestion",
+		"cardIndex":       action.CardIndex,
+		"inputResources":  action.InputResources,
+		"outputResources": action.OutputResources,
+		"levels":          action.OutputResources.GetLevels() - action.InputResources.GetLevels(),
+	}
+	if data, err := json.Marshal(reply); err == nil {
+		session.SendToPlayer(playerID, data)
+	}
+	return nil
+}
+
+// cachedPaths are a seat's golem paths as of a number of moves
+type cachedPaths struct {
+	moves int
+	paths []game.GolemPath
+}
+
+// golemPaths works out how many turns playerID needs for each market golem with the
+// cards they own. Like hints it plans the player's turns, so it follows the hint setting.
+// The search is slow, so each seat's result is kept until the next move.
+func (gs *GameSession) golemPaths(playerID int) ([]game.GolemPath, error) {
+	gs.mu.RLock()
+	allowed, moves := gs.hintsAllowed(), gs.moves
+	cached, hit := gs.pathCache[playerID]
+	hit = hit && cached.moves == moves
+	var view *game.GameState
+	if allowed && !hit {
+		view = gs.GameState.ViewFor(playerID)
+	}
+	gs.mu.RUnlock()
+
+	switch {
+	case !allowed:
+		return nil, fmt.Errorf("hints are disabled in this game")
+	case hit:
+		return cached.paths, nil
+	}
+	paths := view.GolemPaths(playerID)
+	gs.mu.Lock()
+	gs.pathCache[playerID] = cachedPaths{moves: moves, paths: paths}
+	gs.mu.Unlock()
+	return paths, nil
+}
+
+// handleGolemPaths answers a "golemPaths" request with the player's path to every market golem
+func handleGolemPaths(session *GameSession, playerID int) error {
+	paths, err := session.golemPaths(playerID)
+	if err != nil {
+		return err
+	}
+	reply := map[string]interface{}{
+		"type":  "golemPaths",
+		"paths": paths,
+	}
+	if data, err := json.Marshal(reply); err == nil {
+		session.SendToPlayer(playerID, data)
+	}
+	return nil
+}
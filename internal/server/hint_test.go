@@ -0,0 +1,31 @@
+package server
+
+import "testing"
+
+// A seat's golem paths are searched once per move
+func TestGolemPathsCached(t *testing.T) {
+	session := NewGameSession("paths", 2, 1)
+	first, err := session.golemPaths(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := session.golemPaths(1)
+	if len(first) == 0 || &first[0] != &again[0] {
+		t.Error("the paths were searched again without a move")
+	}
+	other, _ := session.golemPaths(2)
+	if &other[0] == &first[0] {
+		t.Error("seat 2 got seat 1's paths")
+	}
+
+	session.moves++
+	after, _ := session.golemPaths(1)
+	if &after[0] == &first[0] {
+		t.Error("the paths were not searched again after a move")
+	}
+
+	session.HintsDisabled = true
+	if _, err := session.golemPaths(1); err == nil {
+		t.Error("got paths with hints disabled")
+	}
+}
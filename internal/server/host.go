@@ -0,0 +1,187 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// newToken returns a random hex token for seats and host access
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// ClaimHost makes playerID the host if token matches the session's host token
+func (gs *GameSession) ClaimHost(playerID int, token string) bool {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if token == "" || token != gs.HostToken {
+		return false
+	}
+	gs.HostID = playerID
+	return true
+}
+
+// IsHost reports whether playerID currently holds the host role
+func (gs *GameSession) IsHost(playerID int) bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.HostID != 0 && gs.HostID == playerID
+}
+
+// passHost hands the host role to the lowest connected seat (caller must hold gs.mu)
+func (gs *GameSession) passHost() {
+	gs.HostID = 0
+	ids := make([]int, 0, len(gs.Connections))
+	for id := range gs.Connections {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	if len(ids) > 0 {
+		gs.HostID = ids[0]
+	}
+}
+
+// CheckPasscode reports whether passcode grants access to the room
+func (gs *GameSession) CheckPasscode(passcode string) bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return !gs.Private || passcode == gs.Passcode
+}
+
+// SeatForToken returns the seat that token belongs to (0 if none)
+func (gs *GameSession) SeatForToken(token string) int {
+	if token == "" {
+		return 0
+	}
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	for id, seatToken := range gs.SeatTokens {
+		if seatToken == token {
+			return id
+		}
+	}
+	return 0
+}
+
+// SeatToken returns the token for a seat
+func (gs *GameSession) SeatToken(playerID int) string {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.SeatTokens[playerID]
+}
+
+// Kick disconnects a player. Only the host may kick, and not themselves.
+// The seat's token is rotated and the player's IP and account are banned, so the
+// kicked client can neither reclaim the seat nor be auto-assigned another one.
+func (gs *GameSession) Kick(hostID int, target int) error {
+	gs.mu.Lock()
+	if gs.HostID != hostID {
+		gs.mu.Unlock()
+		return fmt.Errorf("only the host can kick players")
+	}
+	if target == hostID {
+		gs.mu.Unlock()
+		return fmt.Errorf("host cannot kick themselves")
+	}
+	conn, ok := gs.Connections[target]
+	if !ok {
+		gs.mu.Unlock()
+		return fmt.Errorf("player %d is not connected", target)
+	}
+
+	delete(gs.Connections, target)
+	delete(gs.PlayerNames, target)
+	delete(gs.PlayerAvatars, target)
+	// The old seat token no longer takes the seat, and neither it nor the account
+	// gets back in. Anonymous players could come back under a new name anyway, so
+	// addresses are not banned: on a shared network that would lock out everyone.
+	gs.Banned[gs.SeatTokens[target]] = true
+	if conn != nil && conn.userID != "" {
+		gs.Banned[conn.userID] = true
+	}
+	gs.SeatTokens[target] = newToken()
+	gs.mu.Unlock()
+
+	if conn != nil {
+		conn.send([]byte(`{"type":"kicked"}`))
+		conn.conn.Close()
+	}
+	return nil
+}
+
+// IsBanned reports whether a client was kicked from the session, by the seat
+// token it presents or its account
+func (gs *GameSession) IsBanned(seatToken, userID string) bool {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return seatToken != "" && gs.Banned[seatToken] || userID != "" && gs.Banned[userID]
+}
+
+// SetSeatLocked locks or unlocks a seat. A locked seat is reserved:
+// it is skipped by auto-assignment and can only be taken with its seat token,
+// which is returned so the host can share it with the invited player.
+func (gs *GameSession) SetSeatLocked(hostID int, seat int, locked bool) (string, error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.HostID != hostID {
+		return "", fmt.Errorf("only the host can lock seats")
+	}
+	if seat < 1 || seat > len(gs.GameState.Players) {
+		return "", fmt.Errorf("invalid seat %d", seat)
+	}
+	if locked {
+		gs.LockedSeats[seat] = true
+	} else {
+		delete(gs.LockedSeats, seat)
+	}
+	return gs.SeatTokens[seat], nil
+}
+
+// SetPrivate marks the room private (with a passcode) or public
+func (gs *GameSession) SetPrivate(hostID int, private bool, passcode string) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	if gs.HostID != hostID {
+		return fmt.Errorf("only the host can change room privacy")
+	}
+	if private && passcode == "" {
+		return fmt.Errorf("private rooms need a passcode")
+	}
+	gs.Private = private
+	if private {
+		gs.Passcode = passcode
+	} else {
+		gs.Passcode = ""
+	}
+	return nil
+}
+
+// roomInfo returns the host-visible room settings (caller must hold gs.mu)
+func (gs *GameSession) roomInfo() map[string]interface{} {
+	lockedSeats := make([]int, 0, len(gs.LockedSeats))
+	for seat := range gs.LockedSeats {
+		lockedSeats = append(lockedSeats, seat)
+	}
+	sort.Ints(lockedSeats)
+	info := map[string]interface{}{
+		"hostID":         gs.HostID,
+		"private":        gs.Private,
+		"rated":          gs.Rated,
+		"lockedSeats":    lockedSeats,
+		"correspondence": gs.Correspondence,
+		"hints":          gs.hintsAllowed(),
+	}
+	if gs.Correspondence {
+		info["turnDeadlineHours"] = gs.TurnDeadline.Hours()
+		info["deadline"] = gs.Deadline
+	}
+	return info
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+)
+
+// A kick bans the seat token, not the address the player shares with others
+func TestKickBansSeatToken(t *testing.T) {
+	gs := NewGameServer()
+	srv := newTestServer(t, gs)
+	session, err := gs.CreateSession("kick", 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host, _ := dial(t, srv, "/ws?session=kick&hostToken="+session.HostToken)
+	if msg := readType(t, host, "playerAssigned"); msg["isHost"] != true {
+		t.Fatalf("the host token did not make seat %v host", msg["playerID"])
+	}
+	guest, _ := dial(t, srv, "/ws?session=kick&name=Bob")
+	assigned := readType(t, guest, "playerAssigned")
+	token, _ := assigned["seatToken"].(string)
+
+	if err := host.WriteJSON(map[string]interface{}{"type": "kick", "playerID": assigned["playerID"]}); err != nil {
+		t.Fatal(err)
+	}
+	readType(t, guest, "kicked")
+
+	if _, status := dial(t, srv, "/ws?session=kick&token="+token); status != http.StatusForbidden {
+		t.Errorf("rejoin with the kicked seat's token: got %d, want %d", status, http.StatusForbidden)
+	}
+	// Everyone here connects from 127.0.0.1
+	other, status := dial(t, srv, "/ws?session=kick&name=Cat")
+	if other == nil {
+		t.Fatalf("another player from the same address: got %d", status)
+	}
+	readType(t, other, "playerAssigned")
+
+	host.Close()
+	if host, status := dial(t, srv, "/ws?session=kick&token="+token+"&hostToken="+session.HostToken); host == nil {
+		t.Fatalf("host rejoin: got %d", status)
+	} else if msg := readType(t, host, "playerAssigned"); msg["isHost"] != true {
+		t.Error("the host rejoined without the host role")
+	}
+}
+
+// Only the host kicks, and never themselves
+func TestKickRules(t *testing.T) {
+	session := NewGameSession("rules", 2, 1)
+	session.HostID = 1
+	session.Connections[1] = nil
+	session.Connections[2] = nil
+	if err := session.Kick(2, 1); err == nil {
+		t.Error("a guest kicked the host")
+	}
+	if err := session.Kick(1, 1); err == nil {
+		t.Error("the host kicked themselves")
+	}
+	if err := session.Kick(1, 3); err == nil {
+		t.Error("kicked an empty seat")
+	}
+	token := session.SeatTokens[2]
+	if err := session.Kick(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if !session.IsBanned(token, "") || session.SeatForToken(token) != 0 {
+		t.Error("the kicked seat's token still works")
+	}
+	if session.IsBanned("", "") {
+		t.Error("a client with no token or account counts as banned")
+	}
+}
@@ -0,0 +1,347 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golem_century/internal/game"
+	"golem_century/internal/rating"
+
+	"github.com/gorilla/websocket"
+)
+
+// Matchmaking settings
+const (
+	queueTickInterval = time.Second
+	queueBaseWindow   = 100.0            // Rating spread accepted as soon as a player queues
+	queueWindowGrowth = 10.0             // Extra spread accepted per second waited
+	queueMaxWindow    = 1000.0           // The window stops widening here
+	queueTimeout      = 60 * time.Second // After this long, offer to fill the table with bots
+)
+
+// queueTicket is one player waiting in the matchmaking queue
+type queueTicket struct {
+	id         string
+	numPlayers int
+	rated      bool
+	userID     string
+	name       string
+	rating     float64
+	joinedAt   time.Time
+	offered    bool // Bot fill has been offered
+	conn       *websocket.Conn
+	writeMu    sync.Mutex
+}
+
+// send writes a message to the ticket's connection
+func (t *queueTicket) send(msg map[string]interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	t.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	t.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// window returns the rating spread this ticket accepts after waiting until now
+func (t *queueTicket) window(now time.Time) float64 {
+	return math.Min(queueBaseWindow+queueWindowGrowth*now.Sub(t.joinedAt).Seconds(), queueMaxWindow)
+}
+
+// matchmaker holds the queue; tickets are grouped by player count and rated/casual
+type matchmaker struct {
+	tickets map[string]*queueTicket
+	mu      sync.Mutex
+	start   sync.Once
+}
+
+func newMatchmaker() *matchmaker {
+	return &matchmaker{tickets: make(map[string]*queueTicket)}
+}
+
+// remove takes a ticket out of the queue; it reports false if it was already gone (matched)
+func (m *matchmaker) remove(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.tickets[id]; !ok {
+		return false
+	}
+	delete(m.tickets, id)
+	return true
+}
+
+// runMatchmaker forms tables once per tick until the server exits
+func (gs *GameServer) runMatchmaker() {
+	ticker := time.NewTicker(queueTickInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		gs.matchQueue(now)
+	}
+}
+
+// matchQueue pairs waiting players by rating proximity. Within each queue, tickets are
+// sorted by rating and a run of consecutive tickets forms a table when its spread fits
+// every member's window. Windows widen the longer a player waits.
+func (gs *GameServer) matchQueue(now time.Time) {
+	m := gs.queue
+	m.mu.Lock()
+
+	type queueKey struct {
+		numPlayers int
+		rated      bool
+	}
+	queues := make(map[queueKey][]*queueTicket)
+	for _, t := range m.tickets {
+		key := queueKey{t.numPlayers, t.rated}
+		queues[key] = append(queues[key], t)
+	}
+
+	var tables [][]*queueTicket
+	for key, waiting := range queues {
+		sort.Slice(waiting, func(i, j int) bool { return waiting[i].rating < waiting[j].rating })
+		for i := 0; i+key.numPlayers <= len(waiting); {
+			group := waiting[i : i+key.numPlayers]
+			spread := group[len(group)-1].rating - group[0].rating
+			fits := true
+			for _, t := range group {
+				if spread > t.window(now) {
+					fits = false
+					break
+				}
+			}
+			if !fits {
+				i++
+				continue
+			}
+			tables = append(tables, group)
+			for _, t := range group {
+				delete(m.tickets, t.id)
+			}
+			i += key.numPlayers
+		}
+	}
+
+	var timedOut []*queueTicket
+	for _, t := range m.tickets {
+		if !t.offered && now.Sub(t.joinedAt) >= queueTimeout {
+			t.offered = true
+			timedOut = append(timedOut, t)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, group := range tables {
+		gs.startMatch(group, 0, game.BotSettings{})
+	}
+	for _, t := range timedOut {
+		t.send(map[string]interface{}{
+			"type":      "queueTimeout",
+			"waited":    int(now.Sub(t.joinedAt).Seconds()),
+			"offerBots": true,
+		})
+	}
+}
+
+// startMatch creates a session for matched tickets, fills any remaining seats with bots,
+// and tells each player their seat and seat token
+func (gs *GameServer) startMatch(group []*queueTicket, bots int, settings game.BotSettings) {
+	numPlayers := len(group) + bots
+	rated := group[0].rated
+
+	sessionID := "match_" + newToken()[:12]
+	session, err := gs.CreateSession(sessionID, numPlayers, time.Now().UnixNano())
+	if err != nil {
+		log.Printf("Failed to create match %s: %v", sessionID, err)
+		for _, t := range group {
+			t.send(map[string]interface{}{"type": "error", "error": "Server is full, try again later"})
+			t.conn.Close()
+		}
+		return
+	}
+	session.mu.Lock()
+	session.Rated = rated
+	names := make([]string, 0, numPlayers)
+	for i, t := range group {
+		session.LockedSeats[i+1] = true
+		session.GameState.Players[i].Name = t.name
+		names = append(names, t.name)
+	}
+	session.mu.Unlock()
+
+	for seat := len(group) + 1; seat <= numPlayers; seat++ {
+		bot, botSeat, err := gs.newBot(BotSeat{Seat: seat, BotSettings: settings})
+		if err != nil {
+			log.Printf("Failed to create bot for %s: %v", sessionID, err)
+			continue
+		}
+		name := fmt.Sprintf("Bot %d", seat)
+		if err := session.AddBot(seat, name, botSeat.strategy(), bot); err != nil {
+			log.Printf("Failed to add bot to %s: %v", sessionID, err)
+		}
+		names = append(names, name)
+	}
+
+	log.Printf("Matched %d players into %s (rated=%v, bots=%d)", len(group), sessionID, rated, bots)
+	for i, t := range group {
+		t.send(map[string]interface{}{
+			"type":       "matched",
+			"sessionID":  sessionID,
+			"playerID":   i + 1,
+			"seatToken":  session.SeatToken(i + 1),
+			"numPlayers": numPlayers,
+			"rated":      rated,
+			"players":    names,
+		})
+		t.conn.Close()
+	}
+}
+
+// HandleQueue joins the matchmaking queue over a WebSocket
+// (?players=2-5&rated=true&name=...). The server sends "queued", then either
+// "matched" with the session and seat token, or "queueTimeout" offering bots.
+// Clients may send {"type":"fillBots"} after the offer (optionally with "difficulty"
+// and "personality"), or {"type":"leave"}.
+func (gs *GameServer) HandleQueue(w http.ResponseWriter, r *http.Request) {
+	numPlayers, err := strconv.Atoi(r.URL.Query().Get("players"))
+	if err != nil || numPlayers < game.MinPlayers || numPlayers > game.MaxPlayers {
+		sendJSONError(w, http.StatusBadRequest, "Invalid number of players")
+		return
+	}
+	rated := r.URL.Query().Get("rated") == "true"
+	name := r.URL.Query().Get("name")
+	if utf8.RuneCountInString(name) > maxNameLength {
+		sendJSONError(w, http.StatusBadRequest, fmt.Sprintf("Name too long (max %d characters)", maxNameLength))
+		return
+	}
+
+	userID := gs.userFromRequest(r)
+	if rated && (gs.Ratings == nil || gs.Accounts == nil) {
+		sendJSONError(w, http.StatusBadRequest, "Rated games are not enabled on this server")
+		return
+	}
+	if rated && userID == "" {
+		sendJSONError(w, http.StatusUnauthorized, "Rated games require signing in")
+		return
+	}
+	if gs.createLimiter != nil && !gs.createLimiter.Allow(clientIP(r)) {
+		sendRateLimited(w, gs.createLimiter.interval)
+		return
+	}
+
+	playerRating := rating.InitialRating
+	if userID != "" {
+		if name == "" {
+			if user, err := gs.Accounts.Get(userID); err == nil {
+				name = user.Username
+			}
+		}
+		if gs.Ratings != nil {
+			playerRating = gs.Ratings.Get(rating.PlayerKey(userID)).Rating
+		}
+	}
+	if name == "" {
+		name = "Player"
+	}
+
+	conn, err := gs.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxMessageSize)
+
+	ticket := &queueTicket{
+		id:         newToken(),
+		numPlayers: numPlayers,
+		rated:      rated,
+		userID:     userID,
+		name:       name,
+		rating:     playerRating,
+		joinedAt:   time.Now(),
+		conn:       conn,
+	}
+	gs.queue.start.Do(func() { go gs.runMatchmaker() })
+	gs.queue.mu.Lock()
+	gs.queue.tickets[ticket.id] = ticket
+	gs.queue.mu.Unlock()
+	defer gs.queue.remove(ticket.id)
+
+	ticket.send(map[string]interface{}{
+		"type":       "queued",
+		"numPlayers": numPlayers,
+		"rated":      rated,
+		"rating":     playerRating,
+		"timeout":    int(queueTimeout.Seconds()),
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return // Closed by the client, or by startMatch after a match
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(message, &msg); err != nil {
+			continue
+		}
+		switch msg["type"] {
+		case "fillBots":
+			gs.queue.mu.Lock()
+			offered := ticket.offered
+			gs.queue.mu.Unlock()
+			if !offered {
+				ticket.send(map[string]interface{}{"type": "error", "error": "Bots are offered once the queue times out"})
+				continue
+			}
+			difficulty, _ := msg["difficulty"].(string)
+			personality, _ := msg["personality"].(string)
+			settings, err := game.BotSettings{Difficulty: game.Difficulty(difficulty), Personality: game.Personality(personality)}.Normalize()
+			if err != nil {
+				ticket.send(map[string]interface{}{"type": "error", "error": err.Error()})
+				continue
+			}
+			// Only fill if the matcher has not grabbed this ticket in the meantime
+			if gs.queue.remove(ticket.id) {
+				gs.startMatch([]*queueTicket{ticket}, numPlayers-1, settings)
+			}
+		case "leave":
+			return
+		}
+	}
+}
+
+// HandleQueueStatus reports how many players wait in each queue
+func (gs *GameServer) HandleQueueStatus(w http.ResponseWriter, r *http.Request) {
+	gs.queue.mu.Lock()
+	counts := make(map[string]int)
+	for _, t := range gs.queue.tickets {
+		kind := "casual"
+		if t.rated {
+			kind = "rated"
+		}
+		counts[fmt.Sprintf("%d-%s", t.numPlayers, kind)]++
+	}
+	gs.queue.mu.Unlock()
+
+	queues := make([]map[string]interface{}, 0, 2*(game.MaxPlayers-game.MinPlayers+1))
+	for numPlayers := game.MinPlayers; numPlayers <= game.MaxPlayers; numPlayers++ {
+		for _, kind := range []string{"casual", "rated"} {
+			queues = append(queues, map[string]interface{}{
+				"numPlayers": numPlayers,
+				"rated":      kind == "rated",
+				"waiting":    counts[fmt.Sprintf("%d-%s", numPlayers, kind)],
+			})
+		}
+	}
+	writeJSON(w, map[string]interface{}{"queues": queues})
+}
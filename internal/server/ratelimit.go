@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a simple token bucket: capacity burst, refilled at rate tokens/second
+type tokenBucket struct {
+	capacity float64
+	rate     float64
+	tokens   float64
+	last     time.Time
+	mu       sync.Mutex
+}
+
+// newTokenBucket creates a full bucket allowing burst events, refilling one every interval
+func newTokenBucket(burst int, interval time.Duration) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(burst),
+		rate:     1 / interval.Seconds(),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// Allow takes a token if one is available
+func (b *tokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// keyedLimiter keeps one token bucket per key (e.g. client IP)
+type keyedLimiter struct {
+	burst    int
+	interval time.Duration
+	buckets  map[string]*tokenBucket
+	mu       sync.Mutex
+}
+
+// newKeyedLimiter creates a limiter allowing burst events per key, refilling one every interval
+func newKeyedLimiter(burst int, interval time.Duration) *keyedLimiter {
+	return &keyedLimiter{
+		burst:    burst,
+		interval: interval,
+		buckets:  make(map[string]*tokenBucket),
+	}
+}
+
+// Allow takes a token from key's bucket
+func (l *keyedLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		// Drop idle buckets once in a while so the map doesn't grow forever
+		if len(l.buckets) >= 10000 {
+			l.prune()
+		}
+		bucket = newTokenBucket(l.burst, l.interval)
+		l.buckets[key] = bucket
+	}
+	l.mu.Unlock()
+	return bucket.Allow()
+}
+
+// prune removes buckets that have refilled completely (caller must hold l.mu)
+func (l *keyedLimiter) prune() {
+	full := time.Duration(l.burst) * l.interval
+	for key, bucket := range l.buckets {
+		bucket.mu.Lock()
+		idle := time.Since(bucket.last)
+		bucket.mu.Unlock()
+		if idle >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// clientIP returns the remote IP of a request, without the port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"golem_century/internal/history"
+	"golem_century/internal/rating"
+)
+
+// handleGameOver runs once when a session's game ends and records the results
+func (gs *GameServer) handleGameOver(session *GameSession) {
+	log.Printf("Game %s finished after %d turns", session.ID, session.GameState.CurrentTurn+1)
+	gs.recordAccountResults(session)
+	gs.recordRatings(session)
+	gs.archiveGame(session)
+	gs.recordTournamentResult(session)
+}
+
+// archiveGame stores the finished game in the match history
+func (gs *GameServer) archiveGame(session *GameSession) {
+	if gs.History == nil {
+		return
+	}
+	session.mu.RLock()
+	users := make(map[int]string, len(session.PlayerUsers))
+	for seat, userID := range session.PlayerUsers {
+		users[seat] = userID
+	}
+	startedAt := session.StartedAt
+	if startedAt.IsZero() {
+		startedAt = session.CreatedAt
+	}
+	actionLog := append([]history.LogEntry{}, session.ActionLog...)
+	rated := session.Rated
+	session.mu.RUnlock()
+
+	record := history.NewRecord(session.ID, session.GameState, users, rated, startedAt, actionLog)
+	if err := gs.History.Add(record); err != nil {
+		log.Printf("Failed to archive game %s: %v", session.ID, err)
+	}
+}
+
+// HandleHistory lists archived games for a player (?player=<account ID or name>&limit=N)
+func (gs *GameServer) HandleHistory(w http.ResponseWriter, r *http.Request) {
+	if gs.History == nil {
+		sendJSONError(w, http.StatusServiceUnavailable, "Match history is not enabled on this server")
+		return
+	}
+	player := r.URL.Query().Get("player")
+	if player == "" {
+		sendJSONError(w, http.StatusBadRequest, "Missing player")
+		return
+	}
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > 500 {
+			sendJSONError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = n
+	}
+	games := gs.History.ForPlayer(player, limit)
+	writeJSON(w, map[string]interface{}{
+		"player": player,
+		"games":  games,
+		"count":  len(games),
+	})
+}
+
+// HandleGameDetail returns one archived game with stats and the action log (/api/games/{id})
+func (gs *GameServer) HandleGameDetail(w http.ResponseWriter, r *http.Request) {
+	if gs.History == nil {
+		sendJSONError(w, http.StatusServiceUnavailable, "Match history is not enabled on this server")
+		return
+	}
+	record, err := gs.History.Get(r.PathValue("id"))
+	if errors.Is(err, history.ErrNotFound) {
+		sendJSONError(w, http.StatusNotFound, "Game not found")
+		return
+	}
+	if err != nil {
+		sendJSONError(w, http.StatusInternalServerError, "Failed to load game")
+		return
+	}
+	writeJSON(w, record)
+}
+
+// recordRatings updates ratings for a rated session. Bots are rated by strategy;
+// every human seat must belong to a signed-in account, otherwise the game is
+// treated as casual.
+func (gs *GameServer) recordRatings(session *GameSession) {
+	if gs.Ratings == nil || !session.Rated {
+		return
+	}
+
+	players := session.GameState.Players
+	scores := make([]int, len(players))
+	for i, p := range players {
+		scores[i] = p.GetFinalPoints()
+	}
+	placements := rating.Placements(scores)
+
+	session.mu.RLock()
+	participants := make([]rating.Participant, 0, len(players))
+	for i, p := range players {
+		if strategy, ok := session.BotStrategies[p.ID]; ok {
+			participants = append(participants, rating.Participant{
+				Key:       rating.BotKey(strategy),
+				Name:      strategy,
+				Placement: placements[i],
+			})
+			continue
+		}
+		userID := session.PlayerUsers[p.ID]
+		if userID == "" {
+			session.mu.RUnlock()
+			log.Printf("Not rating game %s: seat %d has no account", session.ID, p.ID)
+			return
+		}
+		participants = append(participants, rating.Participant{
+			Key:       rating.PlayerKey(userID),
+			Name:      p.Name,
+			Placement: placements[i],
+		})
+	}
+	session.mu.RUnlock()
+
+	if err := gs.Ratings.RecordGame(participants); err != nil {
+		log.Printf("Failed to record ratings for %s: %v", session.ID, err)
+	}
+}
+
+// HandleLeaderboard lists the top ratings (?kind=player|bot&limit=N)
+func (gs *GameServer) HandleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if gs.Ratings == nil {
+		sendJSONError(w, http.StatusServiceUnavailable, "Ratings are not enabled on this server")
+		return
+	}
+	kind := r.URL.Query().Get("kind")
+	if kind == "" {
+		kind = rating.KindPlayer
+	}
+	if kind != rating.KindPlayer && kind != rating.KindBot {
+		sendJSONError(w, http.StatusBadRequest, "kind must be player or bot")
+		return
+	}
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > 500 {
+			sendJSONError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = n
+	}
+
+	entries := gs.Ratings.Leaderboard(kind, limit)
+	leaderboard := make([]map[string]interface{}, len(entries))
+	for i, e := range entries {
+		leaderboard[i] = map[string]interface{}{
+			"rank":   i + 1,
+			"name":   e.Name,
+			"rating": math.Round(e.Rating),
+			"games":  e.Games,
+			"wins":   e.Wins,
+		}
+	}
+	writeJSON(w, map[string]interface{}{
+		"kind":        kind,
+		"leaderboard": leaderboard,
+	})
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// hostCookiePrefix names the cookie holding a session's host token
+const hostCookiePrefix = "golem_host_"
+
+// newUpgrader creates a WebSocket upgrader that checks origins against the server's list
+func (gs *GameServer) newUpgrader() *websocket.Upgrader {
+	return &websocket.Upgrader{
+		CheckOrigin: gs.checkOrigin,
+	}
+}
+
+// checkOrigin allows requests without an Origin header (non-browser clients),
+// same-origin requests, and origins in AllowedOrigins ("*" allows all)
+func (gs *GameServer) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range gs.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// newCookie creates an HttpOnly cookie, marked Secure when the server runs behind TLS
+func (gs *GameServer) newCookie(name, value string, maxAge time.Duration) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(maxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   gs.SecureCookies,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// SecurityHeaders wraps a handler with basic hardening headers.
+// HSTS is only sent when serving over TLS.
+func SecurityHeaders(next http.Handler, tlsEnabled bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Referrer-Policy", "same-origin")
+		if tlsEnabled {
+			w.Header().Set("Strict-Transport-Security", "max-age=31536000")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RedirectToHTTPS returns a handler that redirects plain HTTP requests to HTTPS on
+// httpsPort, leaving the port out of the URL when it is 443
+func RedirectToHTTPS(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if i := strings.LastIndex(host, ":"); i >= 0 && !strings.HasSuffix(host, "]") {
+			host = host[:i]
+		}
+		if httpsPort != 443 {
+			host = fmt.Sprintf("%s:%d", host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+// certDir serves certificates from a directory by SNI name, autocert-style:
+// <dir>/<hostname>.crt and <dir>/<hostname>.key, falling back to default.crt/default.key.
+// Files are re-read when they change, so certificates can be renewed without a restart.
+type certDir struct {
+	dir   string
+	cache map[string]*cachedCert
+	mu    sync.Mutex
+}
+
+type cachedCert struct {
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// NewCertDirConfig returns a TLS config that picks certificates from dir by server name
+func NewCertDirConfig(dir string) (*tls.Config, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	cd := &certDir{dir: dir, cache: make(map[string]*cachedCert)}
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: cd.getCertificate,
+	}, nil
+}
+
+func (cd *certDir) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	// Server names come from the client; never let them escape the directory
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		name = "default"
+	}
+	if cert, err := cd.load(name); err == nil {
+		return cert, nil
+	}
+	return cd.load("default")
+}
+
+func (cd *certDir) load(name string) (*tls.Certificate, error) {
+	certFile := filepath.Join(cd.dir, name+".crt")
+	keyFile := filepath.Join(cd.dir, name+".key")
+	info, err := os.Stat(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	if cached, ok := cd.cache[name]; ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	cd.cache[name] = &cachedCert{cert: &cert, modTime: info.ModTime()}
+	return &cert, nil
+}
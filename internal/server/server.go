@@ -2,70 +2,153 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 	"sync"
 	"time"
 
+	"golem_century/internal/account"
+	"golem_century/internal/challenge"
 	"golem_century/internal/game"
+	"golem_century/internal/history"
+	"golem_century/internal/outbox"
+	"golem_century/internal/rating"
+	"golem_century/internal/tournament"
 
 	"github.com/gorilla/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for local play
-	},
-}
-
 // GameSession represents a multiplayer game session
 type GameSession struct {
-	ID            string
-	GameState     *game.GameState
-	Engine        *game.Engine
-	Connections   map[int]*websocket.Conn // Player ID -> WebSocket connection
-	PlayerNames   map[int]string          // Player ID -> Player name
-	PlayerAvatars map[int]string          // Player ID -> Avatar number
-	CreatedAt     time.Time               // When session was created
-	LastActivity  time.Time               // Last time someone was in the room
-	mu            sync.RWMutex
-	ActionChan    chan PlayerAction
-	BroadcastChan chan []byte
+	ID             string
+	GameState      *game.GameState
+	Engine         *game.Engine
+	Connections    map[int]*wsConn      // Player ID -> WebSocket connection
+	PlayerNames    map[int]string       // Player ID -> Player name
+	PlayerAvatars  map[int]string       // Player ID -> Avatar number
+	PlayerUsers    map[int]string       // Player ID -> account ID of the signed-in player in that seat
+	CreatedAt      time.Time            // When session was created
+	LastActivity   time.Time            // Last time someone was in the room
+	HostID         int                  // Player ID of the host (0 = no host connected)
+	HostToken      string               // Secret given to the session creator to claim the host role
+	Private        bool                 // Private rooms are hidden from the lobby and require a passcode
+	Passcode       string               // Passcode required to join a private room
+	Rated          bool                 // Rated games update ratings; every human seat must be signed in
+	HintsDisabled  bool                 // Players may not ask for hints (never allowed in rated games)
+	KeepAlive      bool                 // Never deleted for inactivity (e.g. tournament tables)
+	Correspondence bool                 // Correspondence games run without live connections
+	TurnDeadline   time.Duration        // Time each seat has to move in correspondence games
+	Deadline       time.Time            // When the current seat's turn expires (correspondence only)
+	NotifyTargets  map[int]string       // Player ID -> email or webhook URL for turn notifications
+	LockedSeats    map[int]bool         // Seats that can only be taken with their seat token
+	SeatTokens     map[int]string       // Player ID -> secret token used to (re)claim the seat
+	Spectators     map[*wsConn]string   // Spectator connection -> name
+	Banned         map[string]bool      // Seat tokens and account IDs the host kicked; they cannot rejoin
+	Bots           map[int]game.Bot     // Seats played by the server
+	BotStrategies  map[int]string       // Player ID -> strategy the seat's bot is rated as
+	Challenge      *challenge.Challenge // Set for solo challenge games
+	PlayerChat     []ChatMessage        // Recent chat on the player channel
+	SpectatorChat  []ChatMessage        // Recent chat on the spectator channel
+	ActionLog      []history.LogEntry   // Every action taken, for the match archive
+	StartedAt      time.Time            // When the first action was taken
+	mu             sync.RWMutex
+	ActionChan     chan PlayerAction
+	BroadcastChan  chan []byte
+	onGameOver     func(*GameSession)       // Called once when the game ends
+	onTurn         func(*GameSession, int)  // Called when a correspondence turn passes to a seat
+	lastMove       time.Time                // When the last action was applied (paces bots)
+	botThinking    bool                     // A bot is choosing its move (game loop only)
+	moves          int                      // Actions applied so far; versions pathCache
+	pathCache      map[int]cachedPaths      // Player ID -> golem paths worked out at a move
+	seatClaims     map[int]bool             // Seats taken over REST that have not moved yet
+	done           chan struct{}            // Closed by the game loop once the game is over
+	standings      []map[string]interface{} // Final placings, set before done is closed
+}
+
+// wsConn is a session connection with a single writer. gorilla/websocket allows
+// one writer at a time, and the game loop, chat and join/leave broadcasts all
+// write from different goroutines.
+type wsConn struct {
+	conn    *websocket.Conn
+	userID  string // Signed-in account, "" for anonymous players
+	writeMu sync.Mutex
+}
+
+func newWSConn(conn *websocket.Conn) *wsConn {
+	return &wsConn{conn: conn}
+}
+
+// send writes a text message to the connection
+func (c *wsConn) send(message []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return c.conn.WriteMessage(websocket.TextMessage, message)
 }
 
 // PlayerAction represents an action from a player
 type PlayerAction struct {
 	PlayerID int
 	Action   game.Action
+	Result   chan ActionResult // Optional; receives the outcome instead of a WebSocket error
+	bot      bool              // Chosen by a server bot
+	turn     int               // Turn a bot's move was chosen for
+}
+
+// ActionResult is the outcome of a submitted action
+type ActionResult struct {
+	Events []Event
+	Err    error
 }
 
 // NewGameSession creates a new game session
 func NewGameSession(sessionID string, numPlayers int, seed int64) *GameSession {
-	gameState := game.NewGameState(numPlayers, seed)
+	return newGameSession(sessionID, game.NewGameState(numPlayers, seed))
+}
+
+// newGameSession creates a session around a game that has been set up
+func newGameSession(sessionID string, gameState *game.GameState) *GameSession {
 	engine := &game.Engine{
 		GameState: gameState,
 		AI:        nil, // No AI players
 	}
 
+	seatTokens := make(map[int]string)
+	for _, p := range gameState.Players {
+		seatTokens[p.ID] = newToken()
+	}
+
 	now := time.Now()
 	return &GameSession{
 		ID:            sessionID,
 		GameState:     gameState,
 		Engine:        engine,
-		Connections:   make(map[int]*websocket.Conn),
+		Connections:   make(map[int]*wsConn),
 		PlayerNames:   make(map[int]string),
 		PlayerAvatars: make(map[int]string),
+		PlayerUsers:   make(map[int]string),
 		CreatedAt:     now,
 		LastActivity:  now,
+		HostToken:     newToken(),
+		LockedSeats:   make(map[int]bool),
+		SeatTokens:    seatTokens,
+		Spectators:    make(map[*wsConn]string),
+		Banned:        make(map[string]bool),
+		Bots:          make(map[int]game.Bot),
+		BotStrategies: make(map[int]string),
+		NotifyTargets: make(map[int]string),
+		pathCache:     make(map[int]cachedPaths),
+		seatClaims:    make(map[int]bool),
 		ActionChan:    make(chan PlayerAction, 10),
 		BroadcastChan: make(chan []byte, 100),
+		done:          make(chan struct{}),
 	}
 }
 
 // AddPlayer adds a player to the session
-func (gs *GameSession) AddPlayer(playerID int, name string, avatar string, conn *websocket.Conn) {
+func (gs *GameSession) AddPlayer(playerID int, name string, avatar string, conn *wsConn) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
 
@@ -76,6 +159,10 @@ func (gs *GameSession) AddPlayer(playerID int, name string, avatar string, conn
 	}
 	gs.PlayerAvatars[playerID] = avatar
 	gs.LastActivity = time.Now() // Update activity time
+	// The first player in an empty room becomes host until the creator shows up
+	if gs.HostID == 0 {
+		gs.HostID = playerID
+	}
 	// Player IDs are 1-indexed, array is 0-indexed
 	if playerID >= 1 && playerID <= len(gs.GameState.Players) {
 		gs.GameState.Players[playerID-1].Name = name
@@ -83,6 +170,17 @@ func (gs *GameSession) AddPlayer(playerID int, name string, avatar string, conn
 	}
 }
 
+// SetSeatUser binds a seat to a signed-in account ("" for anonymous players)
+func (gs *GameSession) SetSeatUser(playerID int, userID string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	if userID == "" {
+		delete(gs.PlayerUsers, playerID)
+		return
+	}
+	gs.PlayerUsers[playerID] = userID
+}
+
 // RemovePlayer removes a player from the session
 func (gs *GameSession) RemovePlayer(playerID int) {
 	gs.mu.Lock()
@@ -90,54 +188,157 @@ func (gs *GameSession) RemovePlayer(playerID int) {
 	delete(gs.Connections, playerID)
 	delete(gs.PlayerNames, playerID)
 	delete(gs.PlayerAvatars, playerID)
+	if gs.HostID == playerID {
+		gs.passHost()
+	}
 }
 
-// Broadcast sends a message to all connected players
-func (gs *GameSession) Broadcast(message []byte) {
+// removeConnection removes a player only if conn is still the seat's connection.
+// A kicked player's read loop exits after the seat may already have been re-taken.
+func (gs *GameSession) removeConnection(playerID int, conn *wsConn) {
 	gs.mu.RLock()
-	defer gs.mu.RUnlock()
+	current := gs.Connections[playerID]
+	gs.mu.RUnlock()
+	if current != conn {
+		return
+	}
+	gs.RemovePlayer(playerID)
+}
 
+// Broadcast sends a message to all connected players and spectators.
+// Writes happen outside gs.mu so a slow client doesn't hold up the session.
+func (gs *GameSession) Broadcast(message []byte) {
+	gs.mu.RLock()
+	conns := make([]*wsConn, 0, len(gs.Connections)+len(gs.Spectators))
 	for _, conn := range gs.Connections {
 		if conn != nil {
-			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			conn.WriteMessage(websocket.TextMessage, message)
+			conns = append(conns, conn)
 		}
 	}
+	for conn := range gs.Spectators {
+		conns = append(conns, conn)
+	}
+	gs.mu.RUnlock()
+
+	for _, conn := range conns {
+		conn.send(message)
+	}
 }
 
 // SendToPlayer sends a message to a specific player
 func (gs *GameSession) SendToPlayer(playerID int, message []byte) error {
 	gs.mu.RLock()
-	defer gs.mu.RUnlock()
-
 	conn, ok := gs.Connections[playerID]
+	gs.mu.RUnlock()
 	if !ok || conn == nil {
 		return nil
 	}
-
-	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-	return conn.WriteMessage(websocket.TextMessage, message)
+	return conn.send(message)
 }
 
+// Limits applied to clients
+const (
+	DefaultMaxSessions = 200                    // Default cap on concurrent sessions
+	maxMessageSize     = 8 * 1024               // Maximum WebSocket message size in bytes
+	maxNameLength      = 32                     // Maximum player name length
+	actionBurst        = 10                     // WebSocket game messages allowed in a burst
+	actionRefillPeriod = 200 * time.Millisecond // One more game message allowed every period
+	createBurst        = 5                      // Sessions one IP may create in a burst
+	createRefillPeriod = 12 * time.Second       // One more session per IP every period
+	hintBurst          = 3                      // Hints one seat may ask for in a burst
+	hintRefillPeriod   = 20 * time.Second       // One more hint per seat every period
+	loginBurst         = 5                      // Sign-ins and registrations one IP may try in a burst
+	loginRefillPeriod  = 12 * time.Second       // One more sign-in attempt per IP every period
+)
+
 // GameServer manages multiple game sessions
 type GameServer struct {
-	Sessions map[string]*GameSession
-	mu       sync.RWMutex
+	Sessions       map[string]*GameSession
+	ChatFilter     ChatFilter      // Optional profanity filter applied to chat text
+	MaxSessions    int             // Maximum concurrent sessions (0 = unlimited)
+	AllowedOrigins []string        // Extra WebSocket origins allowed besides same-origin ("*" = any)
+	SecureCookies  bool            // Mark cookies Secure (set when serving over TLS)
+	Accounts       *account.Store  // Optional account store; nil disables accounts
+	Signer         *account.Signer // Signs session cookies for accounts
+	Ratings        *rating.Store   // Optional rating store; nil disables ratings
+	History        *history.Store  // Optional match archive; nil disables history
+	AdminToken     string          // Bearer token for the admin API ("" disables it)
+	Outbox         *outbox.Outbox  // Where turn notification emails are written; nil disables them
+	AllowWebhooks  bool            // Allow seats to receive turn notifications by webhook
+	BotWeights     *game.Weights   // Evaluation weights for server bots; nil uses the defaults
+	PublicURL      string          // Base URL of the site for links in emails ("" disables magic links)
+	createLimiter  *keyedLimiter
+	hintLimiter    *keyedLimiter
+	loginLimiter   *keyedLimiter
+	queue          *matchmaker
+	upgrader       *websocket.Upgrader
+	mu             sync.RWMutex
+
+	tournaments       map[string]*tournament.Tournament
+	sessionTournament map[string]string // Session ID -> tournament ID
+	tournamentMu      sync.Mutex
 }
 
 // NewGameServer creates a new game server
 func NewGameServer() *GameServer {
-	return &GameServer{
-		Sessions: make(map[string]*GameSession),
+	gs := &GameServer{
+		Sessions:      make(map[string]*GameSession),
+		MaxSessions:   DefaultMaxSessions,
+		createLimiter: newKeyedLimiter(createBurst, createRefillPeriod),
+		hintLimiter:   newKeyedLimiter(hintBurst, hintRefillPeriod),
+		loginLimiter:  newKeyedLimiter(loginBurst, loginRefillPeriod),
+		queue:         newMatchmaker(),
+
+		tournaments:       make(map[string]*tournament.Tournament),
+		sessionTournament: make(map[string]string),
 	}
+	gs.upgrader = gs.newUpgrader()
+	return gs
+}
+
+// SetCreateRateLimit sets how many sessions one IP may create per minute
+func (gs *GameServer) SetCreateRateLimit(perMinute int) {
+	if perMinute <= 0 {
+		gs.createLimiter = nil
+		return
+	}
+	gs.createLimiter = newKeyedLimiter(perMinute, time.Minute/time.Duration(perMinute))
+}
+
+// SessionCount returns the number of active sessions
+func (gs *GameServer) SessionCount() int {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return len(gs.Sessions)
 }
 
-// CreateSession creates a new game session
-func (gs *GameServer) CreateSession(sessionID string, numPlayers int, seed int64) *GameSession {
+// Errors from creating a session
+var (
+	errServerFull    = errors.New("server is full, too many active sessions")
+	errSessionExists = errors.New("session ID already exists")
+)
+
+// CreateSession creates a new game session. It fails when the ID is taken or
+// MaxSessions sessions are already running.
+func (gs *GameServer) CreateSession(sessionID string, numPlayers int, seed int64) (*GameSession, error) {
 	gs.mu.Lock()
 	defer gs.mu.Unlock()
 
-	session := NewGameSession(sessionID, numPlayers, seed)
+	return gs.addSession(NewGameSession(sessionID, numPlayers, seed), false)
+}
+
+// addSession registers a new session and starts its game loop. A reserved session,
+// such as a tournament table, may go past MaxSessions (caller must hold gs.mu).
+func (gs *GameServer) addSession(session *GameSession, reserved bool) (*GameSession, error) {
+	sessionID := session.ID
+	if _, exists := gs.Sessions[sessionID]; exists {
+		return nil, errSessionExists
+	}
+	if !reserved && gs.MaxSessions > 0 && len(gs.Sessions) >= gs.MaxSessions {
+		return nil, errServerFull
+	}
+	session.onGameOver = gs.handleGameOver
+	session.onTurn = gs.notifyTurn
 	gs.Sessions[sessionID] = session
 
 	// Start game loop
@@ -146,7 +347,7 @@ func (gs *GameServer) CreateSession(sessionID string, numPlayers int, seed int64
 	// Start cleanup timer for empty rooms
 	go gs.startCleanupTimer(sessionID)
 
-	return session
+	return session, nil
 }
 
 // startCleanupTimer starts a timer to clean up empty rooms after 5 minutes
@@ -165,7 +366,11 @@ func (gs *GameServer) startCleanupTimer(sessionID string) {
 			session.mu.RLock()
 			hasPlayers := len(session.Connections) > 0
 			lastActivity := session.LastActivity
+			keepAlive := session.KeepAlive
 			session.mu.RUnlock()
+			if keepAlive {
+				continue
+			}
 
 			// If no players and last activity was more than 5 minutes ago, delete
 			if !hasPlayers {
@@ -203,38 +408,136 @@ func (gs *GameSession) RunGameLoop() {
 	for !gs.GameState.GameOver {
 		select {
 		case action := <-gs.ActionChan:
+			if action.bot {
+				gs.playBotAction(action)
+				continue
+			}
 			// Process player action
-			currentPlayer := gs.GameState.GetCurrentPlayer()
-			if action.PlayerID == currentPlayer.ID {
-				if err := gs.GameState.ExecuteAction(action.Action); err == nil {
-					// DepositCrystals and CollectCrystals don't end the turn
-					// They are intermediate actions before acquiring a card
-					if action.Action.Type != game.DepositCrystals && action.Action.Type != game.CollectCrystals && action.Action.Type != game.CollectAllCrystals {
-						gs.GameState.CheckGameOver()
-						if !gs.GameState.GameOver {
-							gs.GameState.NextTurn()
-						}
-					}
-					gs.BroadcastState()
-				} else {
-					// Send error to player
-					errorMsg := map[string]interface{}{
-						"type":  "error",
-						"error": err.Error(),
-					}
-					if data, err := json.Marshal(errorMsg); err == nil {
-						gs.SendToPlayer(action.PlayerID, data)
-					}
+			if action.PlayerID != gs.GameState.GetCurrentPlayer().ID {
+				if action.Result != nil {
+					action.Result <- ActionResult{Err: errNotYourTurn}
+				}
+				continue
+			}
+			events, err := gs.applyAction(action.Action)
+			if action.Result != nil {
+				action.Result <- ActionResult{Events: events, Err: err}
+				continue
+			}
+			if err != nil {
+				// Send error to player
+				errorMsg := map[string]interface{}{
+					"type":  "error",
+					"error": err.Error(),
+				}
+				if data, err := json.Marshal(errorMsg); err == nil {
+					gs.SendToPlayer(action.PlayerID, data)
 				}
 			}
 
 		case <-ticker.C:
-			// No AI processing - all players are human
+			gs.playBotTurn()
+			gs.checkDeadline()
 		}
 	}
 
 	// Game over - send final state
 	gs.BroadcastState()
+	if gs.onGameOver != nil {
+		gs.onGameOver(gs)
+	}
+	gs.standings = gs.finalStandings()
+	close(gs.done)
+}
+
+// finalStandings lists the seats by placement (called from the game loop)
+func (gs *GameSession) finalStandings() []map[string]interface{} {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	standings := make([]map[string]interface{}, 0, len(gs.GameState.Players))
+	for i, p := range gs.GameState.Standings() {
+		standings = append(standings, map[string]interface{}{
+			"placement": i + 1,
+			"playerID":  p.ID,
+			"name":      p.Name,
+			"points":    p.GetFinalPoints(),
+		})
+	}
+	return standings
+}
+
+// applyAction executes an action for the current player, advances the turn
+// when the action ends it, and broadcasts the new state.
+// It returns the events the action caused. The game changes under gs.mu, so
+// handlers reading the state never see a half-applied action.
+func (gs *GameSession) applyAction(action game.Action) ([]Event, error) {
+	gs.mu.Lock()
+	currentPlayer := gs.GameState.GetCurrentPlayer()
+	description := gs.GameState.DescribeAction(action)
+	turn, round := gs.GameState.CurrentTurn, gs.GameState.Round
+	lastRound := gs.GameState.LastRound
+	if err := gs.GameState.ExecuteAction(action); err != nil {
+		gs.mu.Unlock()
+		return nil, err
+	}
+	gs.lastMove = time.Now()
+	gs.moves++
+	delete(gs.seatClaims, currentPlayer.ID)
+
+	events := []Event{{Type: "action", PlayerID: currentPlayer.ID, Action: description, Turn: turn + 1, Round: round}}
+	if !lastRound && gs.GameState.LastRound {
+		events = append(events, Event{Type: "lastRound", PlayerID: currentPlayer.ID, Round: round})
+	}
+	if currentPlayer.PendingDiscard > 0 {
+		events = append(events, Event{Type: "discardRequired", PlayerID: currentPlayer.ID, Count: currentPlayer.PendingDiscard})
+	}
+	// DepositCrystals and CollectCrystals don't end the turn
+	// They are intermediate actions before acquiring a card
+	turnPassed := false
+	if game.EndsTurn(action.Type) {
+		gs.GameState.CheckGameOver()
+		if gs.Challenge != nil {
+			gs.Challenge.Finish(gs.GameState)
+		}
+		if !gs.GameState.GameOver {
+			gs.GameState.NextTurn()
+			turnPassed = true
+			events = append(events, Event{
+				Type:     "turn",
+				PlayerID: gs.GameState.GetCurrentPlayer().ID,
+				Turn:     gs.GameState.CurrentTurn + 1,
+				Round:    gs.GameState.Round,
+			})
+		} else if winner := gs.GameState.Winner; winner != nil {
+			events = append(events, Event{Type: "gameOver", PlayerID: winner.ID, Points: winner.GetFinalPoints()})
+		}
+	}
+	gs.mu.Unlock()
+
+	gs.logAction(turn, round, currentPlayer, description)
+	if turnPassed {
+		gs.startTurnClock()
+	}
+	gs.BroadcastState()
+	return events, nil
+}
+
+// logAction appends a successful action to the session's action log
+func (gs *GameSession) logAction(turn, round int, player *game.Player, description string) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+	now := time.Now()
+	if gs.StartedAt.IsZero() {
+		gs.StartedAt = now
+	}
+	gs.ActionLog = append(gs.ActionLog, history.LogEntry{
+		Turn:     turn + 1,
+		Round:    round,
+		PlayerID: player.ID,
+		Player:   player.Name,
+		Action:   description,
+		Time:     now,
+	})
 }
 
 // BroadcastState broadcasts the current game state to all players
@@ -260,9 +563,10 @@ func (gs *GameSession) SerializeState() map[string]interface{} {
 			avatar = fmt.Sprintf("%d", p.ID) // Default to player ID
 		}
 		players[i] = map[string]interface{}{
-			"id":     p.ID,
-			"name":   p.Name,
-			"avatar": avatar,
+			"id":         p.ID,
+			"name":       p.Name,
+			"avatar":     avatar,
+			"registered": gs.PlayerUsers[p.ID] != "",
 			"resources": map[string]int{
 				"yellow": p.Resources.Yellow,
 				"green":  p.Resources.Green,
@@ -276,6 +580,7 @@ func (gs *GameSession) SerializeState() map[string]interface{} {
 			"coins":       serializeCards(p.Coins),
 			"hasRested":   p.HasRested,
 			"isAI":        p.IsAI,
+			"trades":      game.PlanTrades(p),
 		}
 	}
 
@@ -309,6 +614,9 @@ func (gs *GameSession) SerializeState() map[string]interface{} {
 	marketPointCards := make([]map[string]interface{}, len(gs.GameState.Market.PointCards))
 	for i, card := range gs.GameState.Market.PointCards {
 		marketPointCards[i] = serializeCard(card)
+		if coin := gs.GameState.Market.CoinOn(i); coin != nil {
+			marketPointCards[i]["coin"] = serializeCard(coin)
+		}
 	}
 
 	marketCoins := serializeCards(gs.GameState.Market.Coins)
@@ -322,6 +630,8 @@ func (gs *GameSession) SerializeState() map[string]interface{} {
 		"lastRound":     gs.GameState.LastRound,
 		"winner":        gs.getWinnerInfo(),
 		"players":       players,
+		"room":          gs.roomInfo(),
+		"challenge":     gs.challengeInfo(),
 		"market": map[string]interface{}{
 			"actionCards": marketActionCards,
 			"pointCards":  marketPointCards,
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestServer serves gs's WebSocket and REST routes
+func newTestServer(t *testing.T, gs *GameServer) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", gs.HandleWebSocket)
+	mux.HandleFunc("/ws/bot", gs.HandleBotWebSocket)
+	mux.HandleFunc("/api/create", gs.HandleCreateSession)
+	mux.HandleFunc("POST /api/games/{id}/seats", gs.HandleTakeSeat)
+	mux.HandleFunc("GET /api/games/{id}/state", gs.HandleGetState)
+	mux.HandleFunc("POST /api/games/{id}/actions", gs.HandleSubmitAction)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// dial opens a WebSocket to path, returning the HTTP status when the server refuses
+func dial(t *testing.T, srv *httptest.Server, path string) (*websocket.Conn, int) {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + path
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		if resp == nil {
+			t.Fatalf("dial %s: %v", path, err)
+		}
+		return nil, resp.StatusCode
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, http.StatusSwitchingProtocols
+}
+
+// readType reads messages until one of type msgType arrives
+func readType(t *testing.T, conn *websocket.Conn, msgType string) map[string]interface{} {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	for {
+		var msg map[string]interface{}
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("waiting for %q: %v", msgType, err)
+		}
+		if msg["type"] == msgType {
+			return msg
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golem_century/internal/rating"
+	"golem_century/internal/tournament"
+)
+
+// requireAdmin checks the "Authorization: Bearer <AdminToken>" header
+func (gs *GameServer) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if gs.AdminToken == "" {
+		sendJSONError(w, http.StatusForbidden, "Admin API is disabled (start the server with -admin-token)")
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(gs.AdminToken)) != 1 {
+		sendJSONError(w, http.StatusUnauthorized, "Invalid admin token")
+		return false
+	}
+	return true
+}
+
+// getTournament looks up the tournament named in the request path
+func (gs *GameServer) getTournament(w http.ResponseWriter, r *http.Request) (*tournament.Tournament, bool) {
+	gs.tournamentMu.Lock()
+	t, ok := gs.tournaments[r.PathValue("id")]
+	gs.tournamentMu.Unlock()
+	if !ok {
+		sendJSONError(w, http.StatusNotFound, "Tournament not found")
+	}
+	return t, ok
+}
+
+// serializeTournament returns the public view: standings and every round's tables
+func serializeTournament(t *tournament.Tournament) map[string]interface{} {
+	rounds := make([]map[string]interface{}, len(t.Rounds))
+	for i, round := range t.Rounds {
+		tables := make([]map[string]interface{}, len(round.Tables))
+		for j, table := range round.Tables {
+			players := make([]string, len(table.Entrants))
+			for k, id := range table.Entrants {
+				players[k] = t.Entrant(id).Name
+			}
+			tables[j] = map[string]interface{}{
+				"number":    table.Number,
+				"players":   players,
+				"entrants":  table.Entrants,
+				"seed":      table.Seed,
+				"sessionID": table.SessionID,
+				"bye":       table.Bye,
+				"done":      table.Done(),
+				"results":   table.Results,
+			}
+		}
+		rounds[i] = map[string]interface{}{
+			"number":    round.Number,
+			"startedAt": round.StartedAt,
+			"done":      round.Done(),
+			"tables":    tables,
+		}
+	}
+	return map[string]interface{}{
+		"id":        t.ID,
+		"name":      t.Name,
+		"format":    t.Format,
+		"tableSize": t.TableSize,
+		"maxRounds": t.MaxRounds,
+		"finished":  t.Finished(),
+		"entrants":  t.Entrants,
+		"standings": t.Standings(),
+		"rounds":    rounds,
+	}
+}
+
+// HandleCreateTournament creates a tournament from a roster (admin)
+func (gs *GameServer) HandleCreateTournament(w http.ResponseWriter, r *http.Request) {
+	if !gs.requireAdmin(w, r) {
+		return
+	}
+
+	var req struct {
+		Name      string   `json:"name"`
+		Format    string   `json:"format"`    // "swiss" or "roundrobin"
+		TableSize int      `json:"tableSize"` // 2-5 players per table
+		Rounds    int      `json:"rounds"`    // 0 = default for the format
+		Seed      int64    `json:"seed"`      // Base seed; each table's seed derives from it
+		Roster    []string `json:"roster"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 64*1024)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if req.Format == "" {
+		req.Format = string(tournament.Swiss)
+	}
+	if req.TableSize == 0 {
+		req.TableSize = 4
+	}
+	if req.Seed == 0 {
+		req.Seed = time.Now().UnixNano()
+	}
+
+	id := newToken()[:8]
+	t, err := tournament.New(id, req.Name, tournament.Format(req.Format), req.TableSize, req.Rounds, req.Seed, req.Roster)
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	gs.tournamentMu.Lock()
+	gs.tournaments[id] = t
+	view := serializeTournament(t)
+	gs.tournamentMu.Unlock()
+
+	writeJSON(w, view)
+}
+
+// HandleGetTournament returns standings and pairings
+func (gs *GameServer) HandleGetTournament(w http.ResponseWriter, r *http.Request) {
+	t, ok := gs.getTournament(w, r)
+	if !ok {
+		return
+	}
+	gs.tournamentMu.Lock()
+	view := serializeTournament(t)
+	gs.tournamentMu.Unlock()
+	writeJSON(w, view)
+}
+
+// HandleStartRound pairs the next round and creates a session per table (admin).
+// Every seat is reserved; the response carries the seat tokens to hand out to players.
+// A player with a bye gets no session.
+func (gs *GameServer) HandleStartRound(w http.ResponseWriter, r *http.Request) {
+	if !gs.requireAdmin(w, r) {
+		return
+	}
+	t, ok := gs.getTournament(w, r)
+	if !ok {
+		return
+	}
+
+	gs.tournamentMu.Lock()
+	defer gs.tournamentMu.Unlock()
+
+	round, err := t.PairNextRound()
+	if err != nil {
+		sendJSONError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	tables := make([]map[string]interface{}, len(round.Tables))
+	for i, table := range round.Tables {
+		if table.Bye {
+			tables[i] = map[string]interface{}{
+				"number":    table.Number,
+				"bye":       true,
+				"entrantID": table.Entrants[0],
+				"name":      t.Entrant(table.Entrants[0]).Name,
+			}
+			continue
+		}
+		table.SessionID = fmt.Sprintf("t-%s-r%d-t%d", t.ID, round.Number, table.Number)
+		// The round is paired already, so its tables may go past MaxSessions
+		gs.mu.Lock()
+		session, err := gs.addSession(NewGameSession(table.SessionID, len(table.Entrants), table.Seed), true)
+		gs.mu.Unlock()
+		if err != nil {
+			sendJSONError(w, http.StatusConflict, fmt.Sprintf("Table %d: %v", table.Number, err))
+			return
+		}
+		gs.sessionTournament[table.SessionID] = t.ID
+
+		seats := make([]map[string]interface{}, len(table.Entrants))
+		session.mu.Lock()
+		session.KeepAlive = true
+		for seat, entrantID := range table.Entrants {
+			name := t.Entrant(entrantID).Name
+			session.GameState.Players[seat].Name = name
+			session.LockedSeats[seat+1] = true
+			seats[seat] = map[string]interface{}{
+				"seat":      seat + 1,
+				"entrantID": entrantID,
+				"name":      name,
+				"seatToken": session.SeatTokens[seat+1],
+			}
+		}
+		session.mu.Unlock()
+
+		tables[i] = map[string]interface{}{
+			"number":    table.Number,
+			"sessionID": table.SessionID,
+			"seed":      table.Seed,
+			"seats":     seats,
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"tournamentID": t.ID,
+		"round":        round.Number,
+		"tables":       tables,
+	})
+}
+
+// HandleDropPlayer removes an entrant from future rounds (admin). A table they are
+// still seated at is ended with HandleTableResult.
+func (gs *GameServer) HandleDropPlayer(w http.ResponseWriter, r *http.Request) {
+	if !gs.requireAdmin(w, r) {
+		return
+	}
+	t, ok := gs.getTournament(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		EntrantID int `json:"entrantID"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	gs.tournamentMu.Lock()
+	err := t.Drop(req.EntrantID)
+	view := serializeTournament(t)
+	gs.tournamentMu.Unlock()
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, view)
+}
+
+// HandleTableResult ends a table of the current round by hand (admin), so a game
+// that cannot finish doesn't hold up the round. The body gives either every seat's
+// result ({"results": [{"entrantID": 3, "placement": 1, "points": 42}, ...]}) or the
+// entrants who forfeit ({"forfeit": [3]}). The table's session is then let go.
+func (gs *GameServer) HandleTableResult(w http.ResponseWriter, r *http.Request) {
+	if !gs.requireAdmin(w, r) {
+		return
+	}
+	t, ok := gs.getTournament(w, r)
+	if !ok {
+		return
+	}
+	number, err := strconv.Atoi(r.PathValue("table"))
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid table number")
+		return
+	}
+
+	var req struct {
+		Results []tournament.Result `json:"results"`
+		Forfeit []int               `json:"forfeit"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxMessageSize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendJSONError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if (req.Results == nil) == (req.Forfeit == nil) {
+		sendJSONError(w, http.StatusBadRequest, "Give either results or forfeit")
+		return
+	}
+
+	gs.tournamentMu.Lock()
+	if req.Results != nil {
+		err = t.SetTableResults(number, req.Results)
+	} else {
+		err = t.Forfeit(number, req.Forfeit)
+	}
+	var sessionID string
+	if err == nil {
+		for _, table := range t.CurrentRound().Tables {
+			if table.Number == number {
+				sessionID = table.SessionID
+			}
+		}
+		log.Printf("Tournament %s: table %d of round %d ended by the admin", t.ID, number, t.CurrentRound().Number)
+	}
+	view := serializeTournament(t)
+	gs.tournamentMu.Unlock()
+	if err != nil {
+		sendJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if session, ok := gs.GetSession(sessionID); ok {
+		session.mu.Lock()
+		session.KeepAlive = false
+		session.mu.Unlock()
+	}
+	writeJSON(w, view)
+}
+
+// recordTournamentResult feeds a finished table back into its tournament
+func (gs *GameServer) recordTournamentResult(session *GameSession) {
+	gs.tournamentMu.Lock()
+	defer gs.tournamentMu.Unlock()
+
+	tournamentID, ok := gs.sessionTournament[session.ID]
+	if !ok {
+		return
+	}
+	t := gs.tournaments[tournamentID]
+	var table *tournament.Table
+	if round := t.CurrentRound(); round != nil {
+		for _, candidate := range round.Tables {
+			if candidate.SessionID == session.ID {
+				table = candidate
+			}
+		}
+	}
+	if table == nil {
+		return
+	}
+
+	scores := make([]int, len(session.GameState.Players))
+	for i, p := range session.GameState.Players {
+		scores[i] = p.GetFinalPoints()
+	}
+	placements := rating.Placements(scores)
+	results := make([]tournament.Result, len(table.Entrants))
+	for seat, entrantID := range table.Entrants {
+		results[seat] = tournament.Result{
+			EntrantID: entrantID,
+			Placement: placements[seat],
+			Points:    scores[seat],
+		}
+	}
+	if t.RecordResults(session.ID, results) {
+		log.Printf("Tournament %s: recorded table %d of round %d", t.ID, table.Number, t.CurrentRound().Number)
+	}
+}
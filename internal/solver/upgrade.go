@@ -0,0 +1,155 @@
+// Package solver finds the best way to use an Upgrade card.
+//
+// It works on plain crystal counts so the game package can use it without an import cycle.
+package solver
+
+// Crystal colours in level order
+const (
+	Yellow = iota
+	Green
+	Blue
+	Pink
+	numColours
+)
+
+// Crystals counts crystals by colour: yellow, green, blue, pink
+type Crystals [numColours]int
+
+// Total returns the number of crystals
+func (c Crystals) Total() int {
+	return c[Yellow] + c[Green] + c[Blue] + c[Pink]
+}
+
+// Levels returns the crystal value: yellow 1, green 2, blue 3, pink 4
+func (c Crystals) Levels() int {
+	return c[Yellow] + 2*c[Green] + 3*c[Blue] + 4*c[Pink]
+}
+
+// Points returns what the crystals score at the end of the game (one per non-yellow crystal)
+func (c Crystals) Points() int {
+	return c[Green] + c[Blue] + c[Pink]
+}
+
+// Upgrade is one use of an Upgrade card: the In crystals become the Out crystals.
+// In and Out share no colour.
+type Upgrade struct {
+	In     Crystals
+	Out    Crystals
+	Levels int // Levels spent, at most the card's budget
+}
+
+// Apply returns the crystals after the upgrade
+func (u Upgrade) Apply(have Crystals) Crystals {
+	for c := range have {
+		have[c] += u.Out[c] - u.In[c]
+	}
+	return have
+}
+
+// Target says what an upgrade should aim for
+type Target struct {
+	// Requirement, when set, is a golem to get as close as possible to.
+	// Without it the upgrade maximises crystal value.
+	Requirement *Crystals
+}
+
+// move upgrades one crystal from colour from to colour to
+type move struct{ from, to int }
+
+var moves = []move{{Yellow, Green}, {Yellow, Blue}, {Yellow, Pink}, {Green, Blue}, {Green, Pink}, {Blue, Pink}}
+
+// Options lists every distinct upgrade of have within budget levels, one per result
+func Options(have Crystals, budget int) []Upgrade {
+	seen := make(map[Crystals]bool)
+	options := make([]Upgrade, 0)
+	var walk func(i int, left Crystals, in, out Crystals, spent int)
+	walk = func(i int, left Crystals, in, out Crystals, spent int) {
+		if i == len(moves) {
+			if spent == 0 {
+				return
+			}
+			u := net(in, out)
+			if result := u.Apply(have); !seen[result] {
+				seen[result] = true
+				options = append(options, u)
+			}
+			return
+		}
+		m := moves[i]
+		cost := m.to - m.from
+		for n := 0; n <= left[m.from] && spent+n*cost <= budget; n++ {
+			l, a, b := left, in, out
+			l[m.from] -= n
+			a[m.from] += n
+			b[m.to] += n
+			walk(i+1, l, a, b, spent+n*cost)
+		}
+	}
+	walk(0, have, Crystals{}, Crystals{}, 0)
+	return options
+}
+
+// net cancels colours that appear on both sides, which a chain of moves
+// such as yellow->green, green->blue produces
+func net(in, out Crystals) Upgrade {
+	for c := range in {
+		common := min(in[c], out[c])
+		in[c] -= common
+		out[c] -= common
+	}
+	return Upgrade{In: in, Out: out, Levels: out.Levels() - in.Levels()}
+}
+
+// Best returns the best upgrade of have within budget levels for target, or false
+// when nothing can be upgraded. Toward a golem it minimises the upgrade steps still
+// missing; otherwise it maximises crystal value. Ties go to the higher crystal value,
+// then to the end-game points, then to touching fewer crystals.
+func Best(have Crystals, budget int, target Target) (Upgrade, bool) {
+	var best Upgrade
+	found := false
+	for _, u := range Options(have, budget) {
+		if !found || better(have, u, best, target) {
+			best, found = u, true
+		}
+	}
+	return best, found
+}
+
+func better(have Crystals, a, b Upgrade, target Target) bool {
+	afterA, afterB := a.Apply(have), b.Apply(have)
+	if target.Requirement != nil {
+		da, db := Steps(afterA, *target.Requirement), Steps(afterB, *target.Requirement)
+		if da != db {
+			return da < db
+		}
+	}
+	if a.Levels != b.Levels {
+		return a.Levels > b.Levels
+	}
+	if pa, pb := afterA.Points(), afterB.Points(); pa != pb {
+		return pa > pb
+	}
+	return a.In.Total() < b.In.Total()
+}
+
+// Steps estimates how many single-level upgrades turn have into a superset of need.
+// Each required crystal is made from the best crystal held at or below its colour;
+// a crystal not held at all counts as a yellow to gain plus its upgrades.
+func Steps(have, need Crystals) int {
+	steps := 0
+	for colour := Pink; colour >= Yellow; colour-- {
+		for ; need[colour] > 0; need[colour]-- {
+			source := colour
+			for source >= Yellow && have[source] == 0 {
+				source--
+			}
+			if source < Yellow {
+				steps += colour + 1
+				continue
+			}
+			have[source]--
+			steps += colour - source
+		}
+	}
+	return steps
+}
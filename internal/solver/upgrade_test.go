@@ -0,0 +1,83 @@
+package solver
+
+import "testing"
+
+func crystals(yellow, green, blue, pink int) Crystals {
+	return Crystals{yellow, green, blue, pink}
+}
+
+func need(yellow, green, blue, pink int) Target {
+	c := crystals(yellow, green, blue, pink)
+	return Target{Requirement: &c}
+}
+
+var bestTests = []struct {
+	name   string
+	have   Crystals
+	budget int
+	target Target
+	want   Upgrade
+}{
+	// Maximising crystal value
+	{"value Y2 budget 1", crystals(2, 0, 0, 0), 1, Target{}, Upgrade{In: crystals(1, 0, 0, 0), Out: crystals(0, 1, 0, 0), Levels: 1}},
+	{"value Y2 budget 2", crystals(2, 0, 0, 0), 2, Target{}, Upgrade{In: crystals(2, 0, 0, 0), Out: crystals(0, 2, 0, 0), Levels: 2}},
+	{"value Y2 budget 3", crystals(2, 0, 0, 0), 3, Target{}, Upgrade{In: crystals(2, 0, 0, 0), Out: crystals(0, 1, 1, 0), Levels: 3}},
+	{"value YG budget 1", crystals(1, 1, 0, 0), 1, Target{}, Upgrade{In: crystals(1, 0, 0, 0), Out: crystals(0, 1, 0, 0), Levels: 1}},
+	{"value G budget 3", crystals(0, 1, 0, 0), 3, Target{}, Upgrade{In: crystals(0, 1, 0, 0), Out: crystals(0, 0, 0, 1), Levels: 2}},
+
+	// Toward a golem, even when it gives up crystal value
+	{"golem GG from Y3 budget 1", crystals(3, 0, 0, 0), 1, need(0, 2, 0, 0), Upgrade{In: crystals(1, 0, 0, 0), Out: crystals(0, 1, 0, 0), Levels: 1}},
+	{"golem GG from Y3 budget 2", crystals(3, 0, 0, 0), 2, need(0, 2, 0, 0), Upgrade{In: crystals(2, 0, 0, 0), Out: crystals(0, 2, 0, 0), Levels: 2}},
+	{"golem GG from Y3 budget 3", crystals(3, 0, 0, 0), 3, need(0, 2, 0, 0), Upgrade{In: crystals(3, 0, 0, 0), Out: crystals(0, 3, 0, 0), Levels: 3}},
+	{"golem YG from Y2 budget 1", crystals(2, 0, 0, 0), 1, need(1, 1, 0, 0), Upgrade{In: crystals(1, 0, 0, 0), Out: crystals(0, 1, 0, 0), Levels: 1}},
+	{"golem YG from Y2 budget 2", crystals(2, 0, 0, 0), 2, need(1, 1, 0, 0), Upgrade{In: crystals(1, 0, 0, 0), Out: crystals(0, 1, 0, 0), Levels: 1}},
+	{"golem YG from Y2 budget 3", crystals(2, 0, 0, 0), 3, need(1, 1, 0, 0), Upgrade{In: crystals(1, 0, 0, 0), Out: crystals(0, 1, 0, 0), Levels: 1}},
+	{"golem B from YG budget 1", crystals(1, 1, 0, 0), 1, need(0, 0, 1, 0), Upgrade{In: crystals(0, 1, 0, 0), Out: crystals(0, 0, 1, 0), Levels: 1}},
+	{"golem B from YG budget 2", crystals(1, 1, 0, 0), 2, need(0, 0, 1, 0), Upgrade{In: crystals(1, 0, 0, 0), Out: crystals(0, 0, 1, 0), Levels: 2}},
+	{"golem B from YG budget 3", crystals(1, 1, 0, 0), 3, need(0, 0, 1, 0), Upgrade{In: crystals(1, 1, 0, 0), Out: crystals(0, 0, 2, 0), Levels: 3}},
+}
+
+func TestBest(t *testing.T) {
+	for _, tt := range bestTests {
+		got, ok := Best(tt.have, tt.budget, tt.target)
+		if !ok {
+			t.Errorf("%s: no upgrade found", tt.name)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+		if got.Levels > tt.budget {
+			t.Errorf("%s: spent %d levels of %d", tt.name, got.Levels, tt.budget)
+		}
+	}
+}
+
+// Pink crystals, or none at all, cannot be upgraded
+func TestBestNothingToUpgrade(t *testing.T) {
+	for budget := 1; budget <= 3; budget++ {
+		for _, have := range []Crystals{{}, crystals(0, 0, 0, 2)} {
+			if u, ok := Best(have, budget, Target{}); ok {
+				t.Errorf("%v budget %d: got %+v, want none", have, budget, u)
+			}
+		}
+	}
+}
+
+func TestSteps(t *testing.T) {
+	tests := []struct {
+		have, need Crystals
+		want       int
+	}{
+		{crystals(0, 2, 0, 0), crystals(0, 2, 0, 0), 0},
+		{crystals(2, 0, 0, 0), crystals(0, 2, 0, 0), 2},
+		{crystals(0, 0, 1, 0), crystals(0, 1, 0, 0), 2}, // A blue can't stand in for a green
+		{crystals(1, 0, 0, 0), crystals(0, 0, 0, 1), 3},
+		{crystals(0, 0, 0, 0), crystals(1, 1, 0, 0), 3},
+	}
+	for _, tt := range tests {
+		if got := Steps(tt.have, tt.need); got != tt.want {
+			t.Errorf("Steps(%v, %v) = %d, want %d", tt.have, tt.need, got, tt.want)
+		}
+	}
+}
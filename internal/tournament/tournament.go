@@ -0,0 +1,502 @@
+package tournament
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Format is the pairing system of a tournament
+type Format string
+
+const (
+	Swiss      Format = "swiss"
+	RoundRobin Format = "roundrobin"
+)
+
+// Entrant is a player in a tournament
+type Entrant struct {
+	ID      int    `json:"id"` // 1-based roster position, also the initial seeding
+	Name    string `json:"name"`
+	Dropped bool   `json:"dropped"`
+}
+
+// Result is one entrant's outcome at a table
+type Result struct {
+	EntrantID int `json:"entrantID"`
+	Placement int `json:"placement"` // 1 = best; equal placements are ties
+	Points    int `json:"points"`    // Final game points
+}
+
+// Table is one game within a round
+type Table struct {
+	Number    int      `json:"number"`
+	Entrants  []int    `json:"entrants"` // Entrant IDs in seat order
+	Seed      int64    `json:"seed"`
+	SessionID string   `json:"sessionID"`
+	Bye       bool     `json:"bye,omitempty"` // The one entrant sits the round out; no game is played
+	Results   []Result `json:"results,omitempty"`
+}
+
+// Done reports whether the table's results are in
+func (t *Table) Done() bool {
+	return len(t.Results) > 0
+}
+
+// Round is a set of tables played at the same time
+type Round struct {
+	Number    int       `json:"number"`
+	Tables    []*Table  `json:"tables"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// Done reports whether every table in the round has finished
+func (r *Round) Done() bool {
+	for _, t := range r.Tables {
+		if !t.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+// Standing is an entrant's position in the tournament
+type Standing struct {
+	Rank        int     `json:"rank"`
+	EntrantID   int     `json:"entrantID"`
+	Name        string  `json:"name"`
+	Dropped     bool    `json:"dropped"`
+	MatchPoints float64 `json:"matchPoints"` // Opponents beaten (+0.5 per tie) summed over games
+	Buchholz    float64 `json:"buchholz"`    // Sum of opponents' match points
+	GamePoints  int     `json:"gamePoints"`  // Final game points summed over games
+	Games       int     `json:"games"`
+}
+
+// Tournament pairs a roster into tables over several rounds
+type Tournament struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Format    Format     `json:"format"`
+	TableSize int        `json:"tableSize"` // Preferred players per table (2-5)
+	MaxRounds int        `json:"maxRounds"`
+	Seed      int64      `json:"seed"`
+	Entrants  []*Entrant `json:"entrants"`
+	Rounds    []*Round   `json:"rounds"`
+}
+
+// New creates a tournament. maxRounds <= 0 picks a default for the format:
+// enough Swiss rounds to find a winner, or enough round-robin rounds for everyone to meet.
+func New(id, name string, format Format, tableSize int, maxRounds int, seed int64, roster []string) (*Tournament, error) {
+	if format != Swiss && format != RoundRobin {
+		return nil, fmt.Errorf("unknown format %q (use swiss or roundrobin)", format)
+	}
+	if tableSize < 2 || tableSize > 5 {
+		return nil, fmt.Errorf("table size must be between 2 and 5")
+	}
+	if len(roster) < 2 {
+		return nil, fmt.Errorf("a tournament needs at least 2 players")
+	}
+
+	entrants := make([]*Entrant, len(roster))
+	seen := make(map[string]bool)
+	for i, name := range roster {
+		if name == "" {
+			return nil, fmt.Errorf("roster entry %d has no name", i+1)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate player %q", name)
+		}
+		seen[name] = true
+		entrants[i] = &Entrant{ID: i + 1, Name: name}
+	}
+
+	if maxRounds <= 0 {
+		if format == Swiss {
+			maxRounds = int(math.Ceil(math.Log2(float64(len(roster)))))
+		} else {
+			maxRounds = int(math.Ceil(float64(len(roster)-1) / float64(tableSize-1)))
+		}
+		if maxRounds < 1 {
+			maxRounds = 1
+		}
+	}
+
+	return &Tournament{
+		ID:        id,
+		Name:      name,
+		Format:    format,
+		TableSize: tableSize,
+		MaxRounds: maxRounds,
+		Seed:      seed,
+		Entrants:  entrants,
+		Rounds:    make([]*Round, 0),
+	}, nil
+}
+
+// Entrant returns an entrant by ID
+func (t *Tournament) Entrant(id int) *Entrant {
+	if id < 1 || id > len(t.Entrants) {
+		return nil
+	}
+	return t.Entrants[id-1]
+}
+
+// CurrentRound returns the latest round (nil before the first)
+func (t *Tournament) CurrentRound() *Round {
+	if len(t.Rounds) == 0 {
+		return nil
+	}
+	return t.Rounds[len(t.Rounds)-1]
+}
+
+// Finished reports whether all rounds have been played
+func (t *Tournament) Finished() bool {
+	current := t.CurrentRound()
+	return len(t.Rounds) >= t.MaxRounds && current != nil && current.Done()
+}
+
+// Drop removes an entrant from future pairings. Results already played still count.
+func (t *Tournament) Drop(entrantID int) error {
+	e := t.Entrant(entrantID)
+	if e == nil {
+		return fmt.Errorf("unknown entrant %d", entrantID)
+	}
+	e.Dropped = true
+	return nil
+}
+
+// PairNextRound creates the next round's tables. Sessions are created by the caller,
+// which fills in each table's SessionID.
+func (t *Tournament) PairNextRound() (*Round, error) {
+	if current := t.CurrentRound(); current != nil && !current.Done() {
+		return nil, fmt.Errorf("round %d is still in progress", current.Number)
+	}
+	if len(t.Rounds) >= t.MaxRounds {
+		return nil, fmt.Errorf("all %d rounds have been played", t.MaxRounds)
+	}
+
+	active := make([]int, 0, len(t.Entrants))
+	for _, e := range t.Entrants {
+		if !e.Dropped {
+			active = append(active, e.ID)
+		}
+	}
+	if len(active) < 2 {
+		return nil, fmt.Errorf("not enough active players to pair a round")
+	}
+
+	var groups [][]int
+	var bye int
+	if t.Format == Swiss {
+		groups, bye = t.pairSwiss(active)
+	} else {
+		groups, bye = t.pairRoundRobin(active)
+	}
+
+	number := len(t.Rounds) + 1
+	round := &Round{Number: number, StartedAt: time.Now()}
+	for i, group := range groups {
+		round.Tables = append(round.Tables, &Table{
+			Number:   i + 1,
+			Entrants: group,
+			Seed:     t.Seed + int64(number)*1000 + int64(i+1),
+		})
+	}
+	if bye != 0 {
+		// The bye is recorded straight away, so it never holds up the round
+		round.Tables = append(round.Tables, &Table{
+			Number:   len(groups) + 1,
+			Entrants: []int{bye},
+			Bye:      true,
+			Results:  []Result{{EntrantID: bye, Placement: 1}},
+		})
+	}
+	t.Rounds = append(t.Rounds, round)
+	return round, nil
+}
+
+// RecordResults stores the results of a table, found by session ID.
+// It returns false if no table in the current round uses that session.
+func (t *Tournament) RecordResults(sessionID string, results []Result) bool {
+	current := t.CurrentRound()
+	if current == nil {
+		return false
+	}
+	for _, table := range current.Tables {
+		if table.SessionID == sessionID && !table.Done() {
+			table.Results = results
+			return true
+		}
+	}
+	return false
+}
+
+// SetTableResults records the results of a table in the current round by hand, e.g.
+// when a game cannot be finished. results must place every entrant at the table once.
+func (t *Tournament) SetTableResults(tableNumber int, results []Result) error {
+	table, err := t.openTable(tableNumber)
+	if err != nil {
+		return err
+	}
+	if len(results) != len(table.Entrants) {
+		return fmt.Errorf("table %d has %d players, got %d results", tableNumber, len(table.Entrants), len(results))
+	}
+	seated := make(map[int]bool, len(table.Entrants))
+	for _, id := range table.Entrants {
+		seated[id] = true
+	}
+	for _, r := range results {
+		if !seated[r.EntrantID] {
+			return fmt.Errorf("entrant %d is not at table %d, or has two results", r.EntrantID, tableNumber)
+		}
+		if r.Placement < 1 || r.Placement > len(table.Entrants) {
+			return fmt.Errorf("placement %d of entrant %d is not between 1 and %d", r.Placement, r.EntrantID, len(table.Entrants))
+		}
+		delete(seated, r.EntrantID)
+	}
+	table.Results = results
+	return nil
+}
+
+// Forfeit ends a table in the current round without a game: the forfeiting entrants
+// tie for last and everyone else ties for first, with no game points. A table where
+// everyone forfeits is a tie.
+func (t *Tournament) Forfeit(tableNumber int, forfeits []int) error {
+	table, err := t.openTable(tableNumber)
+	if err != nil {
+		return err
+	}
+	forfeited := make(map[int]bool, len(forfeits))
+	for _, id := range forfeits {
+		forfeited[id] = true
+	}
+	stay := 0
+	for _, id := range table.Entrants {
+		if !forfeited[id] {
+			stay++
+		}
+	}
+	if len(table.Entrants)-stay != len(forfeited) {
+		return fmt.Errorf("forfeiting entrants must all be at table %d", tableNumber)
+	}
+	results := make([]Result, len(table.Entrants))
+	for i, id := range table.Entrants {
+		results[i] = Result{EntrantID: id, Placement: 1}
+		if forfeited[id] && stay > 0 {
+			results[i].Placement = stay + 1
+		}
+	}
+	table.Results = results
+	return nil
+}
+
+// openTable returns a table of the current round that has no results yet
+func (t *Tournament) openTable(tableNumber int) (*Table, error) {
+	current := t.CurrentRound()
+	if current == nil {
+		return nil, fmt.Errorf("no round has been started")
+	}
+	for _, table := range current.Tables {
+		if table.Number != tableNumber {
+			continue
+		}
+		if table.Done() {
+			return nil, fmt.Errorf("table %d already has results", tableNumber)
+		}
+		return table, nil
+	}
+	return nil, fmt.Errorf("round %d has no table %d", current.Number, tableNumber)
+}
+
+// Standings ranks entrants by match points, then Buchholz, then game points, then seeding
+func (t *Tournament) Standings() []Standing {
+	byID := make(map[int]*Standing, len(t.Entrants))
+	opponents := make(map[int][]int)
+	for _, e := range t.Entrants {
+		byID[e.ID] = &Standing{EntrantID: e.ID, Name: e.Name, Dropped: e.Dropped}
+	}
+
+	for _, round := range t.Rounds {
+		for _, table := range round.Tables {
+			if table.Bye {
+				// A bye counts as a game won against one opponent
+				byID[table.Entrants[0]].MatchPoints++
+			}
+			for _, r := range table.Results {
+				s := byID[r.EntrantID]
+				s.Games++
+				s.GamePoints += r.Points
+				for _, other := range table.Results {
+					if other.EntrantID == r.EntrantID {
+						continue
+					}
+					opponents[r.EntrantID] = append(opponents[r.EntrantID], other.EntrantID)
+					switch {
+					case r.Placement < other.Placement:
+						s.MatchPoints++
+					case r.Placement == other.Placement:
+						s.MatchPoints += 0.5
+					}
+				}
+			}
+		}
+	}
+	for id, s := range byID {
+		for _, opp := range opponents[id] {
+			s.Buchholz += byID[opp].MatchPoints
+		}
+	}
+
+	standings := make([]Standing, 0, len(byID))
+	for _, e := range t.Entrants {
+		standings = append(standings, *byID[e.ID])
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.MatchPoints != b.MatchPoints {
+			return a.MatchPoints > b.MatchPoints
+		}
+		if a.Buchholz != b.Buchholz {
+			return a.Buchholz > b.Buchholz
+		}
+		if a.GamePoints != b.GamePoints {
+			return a.GamePoints > b.GamePoints
+		}
+		return a.EntrantID < b.EntrantID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
+
+// tableSizes splits n players into tables of at most size players, as evenly as possible.
+// With tables of 2 and an odd n the last table has a single player, who gets a bye.
+func tableSizes(n, size int) []int {
+	count := (n + size - 1) / size
+	sizes := make([]int, count)
+	for i := range sizes {
+		sizes[i] = n / count
+		if i < n%count {
+			sizes[i]++
+		}
+	}
+	return sizes
+}
+
+// meetings counts how often each pair of entrants has shared a table
+func (t *Tournament) meetings() map[[2]int]int {
+	met := make(map[[2]int]int)
+	for _, round := range t.Rounds {
+		for _, table := range round.Tables {
+			for i, a := range table.Entrants {
+				for _, b := range table.Entrants[i+1:] {
+					met[pairKey(a, b)]++
+				}
+			}
+		}
+	}
+	return met
+}
+
+func pairKey(a, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
+
+// pairSwiss groups players with similar standings, top tables first,
+// skipping down the standings where that avoids rematches
+func (t *Tournament) pairSwiss(active []int) ([][]int, int) {
+	isActive := make(map[int]bool, len(active))
+	for _, id := range active {
+		isActive[id] = true
+	}
+	ordered := make([]int, 0, len(active))
+	for _, s := range t.Standings() {
+		if isActive[s.EntrantID] {
+			ordered = append(ordered, s.EntrantID)
+		}
+	}
+	return t.group(ordered)
+}
+
+// pairRoundRobin builds tables of players who have met each other least,
+// so that over enough rounds everyone shares a table with everyone else
+func (t *Tournament) pairRoundRobin(active []int) ([][]int, int) {
+	// Rotate the starting order each round so ties break differently
+	order := make([]int, len(active))
+	for i := range active {
+		order[i] = active[(i+len(t.Rounds))%len(active)]
+	}
+	return t.group(order)
+}
+
+// group fills tables in order: each table starts with the first unplaced player
+// and adds, one by one, the earliest remaining player with the fewest previous meetings.
+// A player who would sit alone gets a bye instead, returned as the second result
+// (0 when there is none).
+func (t *Tournament) group(order []int) ([][]int, int) {
+	sizes := tableSizes(len(order), t.TableSize)
+	bye := 0
+	if sizes[len(sizes)-1] == 1 {
+		bye = t.byeFor(order)
+		sizes = sizes[:len(sizes)-1]
+		rest := make([]int, 0, len(order)-1)
+		for _, id := range order {
+			if id != bye {
+				rest = append(rest, id)
+			}
+		}
+		order = rest
+	}
+
+	met := t.meetings()
+	groups := make([][]int, 0)
+	remaining := order
+	for _, size := range sizes {
+		group := []int{remaining[0]}
+		rest := remaining[1:]
+		for len(group) < size {
+			best := 0
+			bestRepeats := -1
+			for i, candidate := range rest {
+				repeats := 0
+				for _, member := range group {
+					repeats += met[pairKey(member, candidate)]
+				}
+				if bestRepeats == -1 || repeats < bestRepeats {
+					best, bestRepeats = i, repeats
+				}
+				if repeats == 0 {
+					break
+				}
+			}
+			group = append(group, rest[best])
+			rest = append(append([]int{}, rest[:best]...), rest[best+1:]...)
+		}
+		groups = append(groups, group)
+		remaining = rest
+	}
+	return groups, bye
+}
+
+// byeFor picks who sits out: the last player in order who hasn't had a bye yet
+func (t *Tournament) byeFor(order []int) int {
+	had := make(map[int]bool)
+	for _, round := range t.Rounds {
+		for _, table := range round.Tables {
+			if table.Bye {
+				had[table.Entrants[0]] = true
+			}
+		}
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		if !had[order[i]] {
+			return order[i]
+		}
+	}
+	return order[len(order)-1]
+}
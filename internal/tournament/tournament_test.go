@@ -0,0 +1,72 @@
+package tournament
+
+import "testing"
+
+func TestOddPlayerGetsBye(t *testing.T) {
+	tour, err := New("t", "Test", Swiss, 2, 3, 1, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byes := make(map[int]bool)
+	for number := 1; number <= 2; number++ {
+		round, err := tour.PairNextRound()
+		if err != nil {
+			t.Fatalf("round %d: %v", number, err)
+		}
+		var bye *Table
+		for _, table := range round.Tables {
+			switch {
+			case table.Bye:
+				bye = table
+			case len(table.Entrants) < 2:
+				t.Fatalf("round %d: table %d has %d players", number, table.Number, len(table.Entrants))
+			default:
+				if err := tour.Forfeit(table.Number, table.Entrants[1:]); err != nil {
+					t.Fatalf("round %d: %v", number, err)
+				}
+			}
+		}
+		if bye == nil || !bye.Done() {
+			t.Fatalf("round %d: no recorded bye", number)
+		}
+		if byes[bye.Entrants[0]] {
+			t.Errorf("round %d: entrant %d got a second bye", number, bye.Entrants[0])
+		}
+		byes[bye.Entrants[0]] = true
+		if !round.Done() {
+			t.Fatalf("round %d is not done", number)
+		}
+	}
+}
+
+func TestSetTableResults(t *testing.T) {
+	tour, err := New("t", "Test", Swiss, 3, 1, 1, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	round, err := tour.PairNextRound()
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := round.Tables[0]
+	if err := tour.SetTableResults(table.Number, []Result{{EntrantID: table.Entrants[0], Placement: 1}}); err == nil {
+		t.Error("results missing two seats were accepted")
+	}
+	results := []Result{
+		{EntrantID: table.Entrants[0], Placement: 2, Points: 30},
+		{EntrantID: table.Entrants[1], Placement: 1, Points: 40},
+		{EntrantID: table.Entrants[2], Placement: 3, Points: 20},
+	}
+	if err := tour.SetTableResults(table.Number, results); err != nil {
+		t.Fatal(err)
+	}
+	if !tour.Finished() {
+		t.Error("tournament not finished after its only table")
+	}
+	if err := tour.SetTableResults(table.Number, results); err == nil {
+		t.Error("a finished table took results twice")
+	}
+	if top := tour.Standings()[0]; top.EntrantID != table.Entrants[1] {
+		t.Errorf("entrant %d leads, want %d", top.EntrantID, table.Entrants[1])
+	}
+}
@@ -0,0 +1,189 @@
+package botclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/gorilla/websocket"
+)
+
+// Bot picks moves. ChooseAction returns the ID of one of turn.LegalActions and
+// must answer within turn.TimeoutMs, or the server makes the bot rest.
+type Bot interface {
+	ChooseAction(turn *Turn) int
+}
+
+// Observer is optionally implemented by bots that want the other server messages
+type Observer interface {
+	Welcome(sessionID string, playerID int, seatToken string)
+	GameOver(standings []Standing)
+}
+
+// BotFunc adapts a function to the Bot interface
+type BotFunc func(turn *Turn) int
+
+// ChooseAction calls f
+func (f BotFunc) ChooseAction(turn *Turn) int {
+	return f(turn)
+}
+
+// FirstLegal is a trivial bot that always picks the first legal action
+var FirstLegal = BotFunc(func(turn *Turn) int {
+	return turn.LegalActions[0].ID
+})
+
+// Config says where the bot plays
+type Config struct {
+	ServerURL string // e.g. ws://localhost:8080
+	SessionID string
+	Name      string
+	SeatToken string // Optional; takes a reserved seat or reclaims the bot's seat
+	Passcode  string // For private rooms
+}
+
+// URL returns the bot endpoint for cfg
+func (cfg Config) URL() (string, error) {
+	u, err := url.Parse(cfg.ServerURL)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+	u.Path = "/ws/bot"
+	q := url.Values{}
+	q.Set("session", cfg.SessionID)
+	if cfg.Name != "" {
+		q.Set("name", cfg.Name)
+	}
+	if cfg.SeatToken != "" {
+		q.Set("token", cfg.SeatToken)
+	}
+	if cfg.Passcode != "" {
+		q.Set("passcode", cfg.Passcode)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// transport carries JSON messages to and from the server
+type transport interface {
+	read() ([]byte, error)
+	write(data []byte) error
+}
+
+// RunWebSocket connects bot to the server and plays until the game ends,
+// the connection drops or ctx is cancelled
+func RunWebSocket(ctx context.Context, cfg Config, bot Bot) error {
+	endpoint, err := cfg.URL()
+	if err != nil {
+		return err
+	}
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, endpoint, nil)
+	if err != nil {
+		if resp != nil {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("connecting to %s: %s: %s", endpoint, resp.Status, body)
+		}
+		return err
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	if err := run(wsTransport{conn}, bot); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// RunStdio plays over line-delimited JSON: server messages are read from r,
+// replies are written to w
+func RunStdio(r io.Reader, w io.Writer, bot Bot) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return run(&stdioTransport{scanner: scanner, w: w}, bot)
+}
+
+// run handles server messages until gameOver or a transport error
+func run(t transport, bot Bot) error {
+	observer, _ := bot.(Observer)
+	for {
+		data, err := t.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var msg message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return fmt.Errorf("invalid server message: %w", err)
+		}
+
+		switch msg.Type {
+		case "welcome":
+			if observer != nil {
+				observer.Welcome(msg.SessionID, msg.PlayerID, msg.SeatToken)
+			}
+		case "turn":
+			turn := msg.Turn
+			actionID := bot.ChooseAction(&turn)
+			data, err := json.Marshal(reply{Type: "action", RequestID: turn.RequestID, ActionID: actionID})
+			if err != nil {
+				return err
+			}
+			if err := t.write(data); err != nil {
+				return err
+			}
+		case "gameOver":
+			if observer != nil {
+				observer.GameOver(msg.Standings)
+			}
+			return nil
+		case "error":
+			// Errors are informational (e.g. a timed-out turn); keep playing
+		}
+	}
+}
+
+type wsTransport struct {
+	conn *websocket.Conn
+}
+
+func (t wsTransport) read() ([]byte, error) {
+	_, data, err := t.conn.ReadMessage()
+	return data, err
+}
+
+func (t wsTransport) write(data []byte) error {
+	return t.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+type stdioTransport struct {
+	scanner *bufio.Scanner
+	w       io.Writer
+}
+
+func (t *stdioTransport) read() ([]byte, error) {
+	if !t.scanner.Scan() {
+		if err := t.scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return t.scanner.Bytes(), nil
+}
+
+func (t *stdioTransport) write(data []byte) error {
+	_, err := t.w.Write(append(data, '\n'))
+	return err
+}
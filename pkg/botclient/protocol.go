@@ -0,0 +1,119 @@
+// Package botclient drives a Century: Golem Edition bot against the server's bot API.
+//
+// A bot implements Bot and is run with RunWebSocket, which connects to /ws/bot, or
+// with RunStdio, which speaks the same JSON messages one per line (useful when a
+// separate process bridges the connection, or for bots written in other languages
+// behind a small Go shim). See docs/BOT_PROTOCOL.md for the wire format.
+package botclient
+
+// Resources is a set of crystals
+type Resources struct {
+	Yellow int `json:"yellow"`
+	Green  int `json:"green"`
+	Blue   int `json:"blue"`
+	Pink   int `json:"pink"`
+}
+
+// Card is the public description of a card
+type Card struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	ActionType  string     `json:"actionType,omitempty"` // produce, upgrade or trade
+	Input       *Resources `json:"input,omitempty"`
+	Output      *Resources `json:"output,omitempty"`
+	TurnUpgrade int        `json:"turnUpgrade,omitempty"`
+	Requirement *Resources `json:"requirement,omitempty"`
+	Points      int        `json:"points,omitempty"`
+	Cost        *Resources `json:"cost,omitempty"`
+}
+
+// Opponent is what the bot can see of another player
+type Opponent struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Resources   Resources `json:"resources"`
+	HandSize    int       `json:"handSize"`
+	PlayedCards []Card    `json:"playedCards"`
+	PointCards  []Card    `json:"pointCards"`
+	Points      int       `json:"points"`
+}
+
+// Observation is the bot's view of the game
+type Observation struct {
+	PlayerID         int        `json:"playerID"`
+	Turn             int        `json:"turn"`
+	Round            int        `json:"round"`
+	LastRound        bool       `json:"lastRound"`
+	Resources        Resources  `json:"resources"`
+	Hand             []Card     `json:"hand"`
+	PlayedCards      []Card     `json:"playedCards"`
+	PointCards       []Card     `json:"pointCards"`
+	Points           int        `json:"points"`
+	PendingDiscard   int        `json:"pendingDiscard"`
+	Opponents        []Opponent `json:"opponents"`
+	MarketCards      []Card     `json:"marketCards"`
+	MarketPointCards []Card     `json:"marketPointCards"`
+	CoinsLeft        []int      `json:"coinsLeft"`
+	CoinPoints       []int      `json:"coinPoints"`
+	ActionDeckSize   int        `json:"actionDeckSize"`
+	PointDeckSize    int        `json:"pointDeckSize"`
+}
+
+// Action mirrors the server's action: Type is playCard, acquireCard, claimPointCard or rest
+type Action struct {
+	Type            string     `json:"type"`
+	CardIndex       int        `json:"cardIndex"`
+	Multiplier      int        `json:"multiplier,omitempty"`
+	InputResources  *Resources `json:"inputResources,omitempty"`
+	OutputResources *Resources `json:"outputResources,omitempty"`
+}
+
+// LegalAction is one action the bot may pick, identified by ID
+type LegalAction struct {
+	ID          int    `json:"id"`
+	Action      Action `json:"action"`
+	Description string `json:"description"`
+}
+
+// Turn asks the bot to move
+type Turn struct {
+	RequestID    int           `json:"requestID"`
+	TimeoutMs    int           `json:"timeoutMs"`
+	Observation  Observation   `json:"observation"`
+	LegalActions []LegalAction `json:"legalActions"`
+}
+
+// Standing is one player's final result
+type Standing struct {
+	Placement int    `json:"placement"`
+	PlayerID  int    `json:"playerID"`
+	Name      string `json:"name"`
+	Points    int    `json:"points"`
+}
+
+// message is any server message; Type says which fields are set
+type message struct {
+	Type string `json:"type"`
+
+	// welcome
+	SessionID  string `json:"sessionID"`
+	PlayerID   int    `json:"playerID"`
+	SeatToken  string `json:"seatToken"`
+	NumPlayers int    `json:"numPlayers"`
+
+	// turn
+	Turn
+
+	// gameOver
+	Standings []Standing `json:"standings"`
+
+	// error
+	Error string `json:"error"`
+}
+
+// reply answers a turn request
+type reply struct {
+	Type      string `json:"type"`
+	RequestID int    `json:"requestID"`
+	ActionID  int    `json:"actionID"`
+}